@@ -0,0 +1,270 @@
+package elastichash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+var (
+	UnsupportedVersionErr = errors.New("unsupported binary format version")
+	InvalidFormatErr      = errors.New("invalid binary format")
+)
+
+const (
+	binaryMagic   = "EHT\x00"
+	binaryVersion = 1
+
+	// maxFieldLen bounds a single encoded key or value so that corrupt input
+	// cannot make the decoder allocate without limit.
+	maxFieldLen = 1 << 26
+)
+
+// binaryHeader starts every binary encoding. Fields are big-endian.
+type binaryHeader struct {
+	Magic    [4]byte
+	Version  uint16
+	Capacity uint64
+	Delta    float64
+	Count    uint64
+}
+
+// MarshalBinary encodes the table as a header carrying the format version,
+// capacity, delta and entry count, followed by each live entry as a
+// length-prefixed key and value. Keys and values must be booleans, numbers or
+// strings, and the capacity is limited as for MarshalJSON.
+func (ht *HashTable[K, V]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := ht.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary rebuilds the table from MarshalBinary output, following the
+// same rules as UnmarshalJSON. Data written by an unknown format version is
+// rejected with UnsupportedVersionErr.
+func (ht *HashTable[K, V]) UnmarshalBinary(data []byte) error {
+	_, err := ht.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+// WriteTo streams the binary encoding of the table to w one entry at a time,
+// without materializing it, and returns the number of bytes written.
+func (ht *HashTable[K, V]) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := ht.writeBinary(cw)
+	return cw.n, err
+}
+
+// ReadFrom rebuilds the table from a binary encoding read from r, following
+// the same rules as UnmarshalBinary, and returns the number of bytes read. It
+// reads no further than the end of the table, so several tables can be read
+// back to back from one stream.
+func (ht *HashTable[K, V]) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	err := ht.readBinary(cr)
+	return cr.n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// countingReader counts the bytes read through it. ReadByte lets
+// binary.ReadUvarint consume lengths without buffering past the table.
+type countingReader struct {
+	r   io.Reader
+	n   int64
+	one [1]byte
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+func (cr *countingReader) ReadByte() (byte, error) {
+	if _, err := io.ReadFull(cr, cr.one[:]); err != nil {
+		return 0, err
+	}
+	return cr.one[0], nil
+}
+
+func (ht *HashTable[K, V]) writeBinary(w io.Writer) error {
+	if err := ht.checkEncodable(); err != nil {
+		return err
+	}
+	header := binaryHeader{
+		Version:  binaryVersion,
+		Capacity: uint64(ht.capacity),
+		Delta:    ht.delta,
+		Count:    uint64(ht.Len()),
+	}
+	copy(header.Magic[:], binaryMagic)
+	if err := binary.Write(w, binary.BigEndian, header); err != nil {
+		return err
+	}
+	var err error
+	var buf []byte
+	ht.Range(func(key K, value V) bool {
+		if buf, err = writeField(w, buf, reflect.ValueOf(key)); err != nil {
+			return false
+		}
+		buf, err = writeField(w, buf, reflect.ValueOf(value))
+		return err == nil
+	})
+	return err
+}
+
+// writeField writes v as a uvarint length followed by its encoding, using buf
+// as scratch space, and returns buf for reuse.
+func writeField(w io.Writer, buf []byte, v reflect.Value) ([]byte, error) {
+	buf, err := appendPrimitive(buf[:0], v)
+	if err != nil {
+		return buf, err
+	}
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(len(buf)))
+	if _, err := w.Write(prefix[:n]); err != nil {
+		return buf, err
+	}
+	_, err = w.Write(buf)
+	return buf, err
+}
+
+func (ht *HashTable[K, V]) readBinary(r *countingReader) error {
+	var header binaryHeader
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return fmt.Errorf("elastichash: reading header: %w", unexpectedEOF(err))
+	}
+	if string(header.Magic[:]) != binaryMagic {
+		return fmt.Errorf("elastichash: bad magic %q: %w", header.Magic[:], InvalidFormatErr)
+	}
+	if header.Version != binaryVersion {
+		return fmt.Errorf("elastichash: version %d: %w", header.Version, UnsupportedVersionErr)
+	}
+	if header.Capacity > uint64(maxSerializedCapacity) || header.Count > header.Capacity {
+		return fmt.Errorf("elastichash: %d entries in capacity %d: %w", header.Count, header.Capacity, InvalidFormatErr)
+	}
+
+	next, err := ht.decodeTarget(int(header.Capacity), header.Delta)
+	if err != nil {
+		return err
+	}
+	var key K
+	var value V
+	for range header.Count {
+		if err := readField(r, reflect.ValueOf(&key).Elem()); err != nil {
+			return err
+		}
+		if err := readField(r, reflect.ValueOf(&value).Elem()); err != nil {
+			return err
+		}
+		if _, _, _, err := next.insert(key, value); err != nil {
+			return fmt.Errorf("elastichash: restoring key %v: %w", key, err)
+		}
+	}
+	ht.adopt(next)
+	return nil
+}
+
+func readField(r *countingReader, v reflect.Value) error {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return fmt.Errorf("elastichash: reading field length: %w", unexpectedEOF(err))
+	}
+	if n > maxFieldLen {
+		return fmt.Errorf("elastichash: field of %d bytes: %w", n, InvalidFormatErr)
+	}
+	field := make([]byte, n)
+	if _, err := io.ReadFull(r, field); err != nil {
+		return fmt.Errorf("elastichash: reading field: %w", unexpectedEOF(err))
+	}
+	return decodePrimitive(field, v)
+}
+
+// unexpectedEOF reports a clean EOF in the middle of a table as the
+// truncation it is.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// appendPrimitive appends the encoding of a boolean, number or string: one
+// byte for booleans, eight big-endian bytes for numbers and the raw bytes of
+// strings.
+func appendPrimitive(b []byte, v reflect.Value) ([]byte, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.BigEndian.AppendUint64(b, uint64(v.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.BigEndian.AppendUint64(b, v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(v.Float())), nil
+	case reflect.String:
+		return append(b, v.String()...), nil
+	}
+	return nil, fmt.Errorf("elastichash: cannot binary-encode %s", v.Type())
+}
+
+// decodePrimitive is the inverse of appendPrimitive; v must be settable.
+func decodePrimitive(b []byte, v reflect.Value) error {
+	bad := func() error {
+		return fmt.Errorf("elastichash: %d bytes do not decode to %s: %w", len(b), v.Type(), InvalidFormatErr)
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if len(b) != 1 {
+			return bad()
+		}
+		v.SetBool(b[0] != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if len(b) != 8 {
+			return bad()
+		}
+		n := int64(binary.BigEndian.Uint64(b))
+		if v.OverflowInt(n) {
+			return bad()
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if len(b) != 8 {
+			return bad()
+		}
+		n := binary.BigEndian.Uint64(b)
+		if v.OverflowUint(n) {
+			return bad()
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		if len(b) != 8 {
+			return bad()
+		}
+		v.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(b)))
+	case reflect.String:
+		v.SetString(string(b))
+	default:
+		return fmt.Errorf("elastichash: cannot binary-decode %s", v.Type())
+	}
+	return nil
+}
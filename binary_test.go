@@ -0,0 +1,131 @@
+package elastichash_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int64](100, 0.1)
+	for i := range 50 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), int64(-i*1000))
+	}
+	data, err := ht.MarshalBinary()
+	require.NoError(t, err)
+
+	var loaded elastichash.HashTable[string, int64]
+	require.NoError(t, loaded.UnmarshalBinary(data))
+	assert.Equal(t, ht.Cap(), loaded.Cap())
+	assert.Equal(t, ht.Len(), loaded.Len())
+	ht.Range(func(k string, v int64) bool {
+		got, ok := loaded.Get(k)
+		assert.True(t, ok)
+		assert.Equal(t, v, got)
+		return true
+	})
+
+	floats := elastichash.NewHashTable[uint16, float32](10, 0.1)
+	mustInsert(t, floats, 65535, 1.5)
+	data, err = floats.MarshalBinary()
+	require.NoError(t, err)
+	var loadedFloats elastichash.HashTable[uint16, float32]
+	require.NoError(t, loadedFloats.UnmarshalBinary(data))
+	v, ok := loadedFloats.Get(65535)
+	assert.True(t, ok)
+	assert.Equal(t, float32(1.5), v)
+}
+
+func TestBinaryRejectsBadInput(t *testing.T) {
+	ht := elastichash.NewHashTable[string, string](10, 0.1)
+	mustInsert(t, ht, "key", "value")
+	data, err := ht.MarshalBinary()
+	require.NoError(t, err)
+
+	for n := range len(data) {
+		var loaded elastichash.HashTable[string, string]
+		assert.Error(t, loaded.UnmarshalBinary(data[:n]), "truncated to %d bytes", n)
+	}
+
+	future := append([]byte(nil), data...)
+	future[5] = 99 // low byte of the big-endian version
+	var loaded elastichash.HashTable[string, string]
+	assert.ErrorIs(t, loaded.UnmarshalBinary(future), elastichash.UnsupportedVersionErr)
+
+	assert.ErrorIs(t, loaded.UnmarshalBinary([]byte("garbage-garbage-garbage-garbage")), elastichash.InvalidFormatErr)
+
+	huge := append([]byte(nil), data...)
+	binary.BigEndian.PutUint64(huge[6:14], 1<<31-1) // capacity, just after the version
+	binary.BigEndian.PutUint64(huge[22:30], 0)      // count
+	assert.ErrorIs(t, loaded.UnmarshalBinary(huge[:30]), elastichash.InvalidFormatErr)
+	assert.Equal(t, 0, loaded.Cap())
+}
+
+func TestBinaryRejectsUnsupportedTypes(t *testing.T) {
+	ht := elastichash.NewHashTable[string, []int](10, 0.1)
+	mustInsert(t, ht, "key", []int{1})
+	_, err := ht.MarshalBinary()
+	assert.Error(t, err)
+}
+
+func TestWriteToReadFrom(t *testing.T) {
+	ht := elastichash.NewHashTable[int, string](100, 0.1)
+	for i := range 40 {
+		mustInsert(t, ht, i, fmt.Sprint(i))
+	}
+	other := elastichash.NewHashTable[int, string](10, 0.1)
+	mustInsert(t, other, 1, "one")
+
+	var buf bytes.Buffer
+	written, err := ht.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), written)
+	_, err = other.WriteTo(&buf)
+	require.NoError(t, err)
+
+	var loaded, loadedOther elastichash.HashTable[int, string]
+	read, err := loaded.ReadFrom(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, written, read, "ReadFrom should stop at the end of the first table")
+	_, err = loadedOther.ReadFrom(&buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, 40, loaded.Len())
+	assert.Equal(t, 1, loadedOther.Len())
+	v, ok := loaded.Get(39)
+	assert.True(t, ok)
+	assert.Equal(t, "39", v)
+}
+
+func TestWriteToReadFromPipe(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](2000, 0.1)
+	for i := range 1500 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+
+	pr, pw := io.Pipe()
+	written := make(chan int64, 1)
+	go func() {
+		n, err := ht.WriteTo(pw)
+		pw.CloseWithError(err)
+		written <- n
+	}()
+
+	var loaded elastichash.HashTable[string, int]
+	read, err := loaded.ReadFrom(pr)
+	require.NoError(t, err)
+	assert.Equal(t, <-written, read)
+	assert.Equal(t, 1500, loaded.Len())
+	for i := range 1500 {
+		v, ok := loaded.Get(fmt.Sprintf("key%d", i))
+		require.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+}
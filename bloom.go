@@ -0,0 +1,60 @@
+package elastichash
+
+import "math/bits"
+
+const (
+	// bloomBitsPerSlot and bloomHashes give about a 1% false-positive rate
+	// with every slot of the table occupied.
+	bloomBitsPerSlot = 10
+	bloomHashes      = 7
+)
+
+// bloomFilter records the hashes of keys inserted since the table's levels
+// were last allocated. Deleting a key leaves its bits set, so the filter only
+// ever errs towards "maybe present"; compaction rebuilds the table and with it
+// the filter, which bounds how stale it gets.
+type bloomFilter struct {
+	bits []uint64
+}
+
+func newBloomFilter(capacity int) *bloomFilter {
+	n := (capacity*bloomBitsPerSlot + 63) / 64
+	return &bloomFilter{bits: make([]uint64, max(n, 1))}
+}
+
+// positions derives the filter's bit positions from h by double hashing. The
+// second hash is h rotated and made odd so it is independent of the low bits
+// the probe sequence uses.
+func (b *bloomFilter) positions(h uint64, f func(pos uint64) bool) {
+	m := uint64(len(b.bits) * 64)
+	h2 := bits.RotateLeft64(h, 32) | 1
+	for i := range uint64(bloomHashes) {
+		if !f((h + i*h2) % m) {
+			return
+		}
+	}
+}
+
+func (b *bloomFilter) add(h uint64) {
+	b.positions(h, func(pos uint64) bool {
+		b.bits[pos/64] |= 1 << (pos % 64)
+		return true
+	})
+}
+
+// mayContain reports false only if no key with hash h has been added.
+func (b *bloomFilter) mayContain(h uint64) bool {
+	found := true
+	b.positions(h, func(pos uint64) bool {
+		found = b.bits[pos/64]&(1<<(pos%64)) != 0
+		return found
+	})
+	return found
+}
+
+func (b *bloomFilter) clone() *bloomFilter {
+	if b == nil {
+		return nil
+	}
+	return &bloomFilter{bits: append([]uint64(nil), b.bits...)}
+}
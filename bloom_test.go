@@ -0,0 +1,90 @@
+package elastichash_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestWithBloomFilter(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](1000, 0.1, elastichash.WithBloomFilter())
+	for i := range 800 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	for i := range 800 {
+		v, ok := ht.Get(fmt.Sprintf("key%d", i))
+		require.True(t, ok, "key%d", i)
+		assert.Equal(t, i, v)
+	}
+
+	rejected := 0
+	for i := range 1000 {
+		_, ok, probes := ht.GetWithProbes(fmt.Sprintf("missing%d", i))
+		require.False(t, ok)
+		if probes == 0 {
+			rejected++
+		}
+	}
+	assert.Greater(t, rejected, 950, "the filter should short-circuit nearly every miss")
+
+	require.True(t, ht.Delete("key3"))
+	assert.False(t, ht.Contains("key3"))
+	mustInsert(t, ht, "key3", 3)
+	assert.True(t, ht.Contains("key3"))
+
+	ht.Reset()
+	_, ok, probes := ht.GetWithProbes("key5")
+	assert.False(t, ok)
+	assert.Zero(t, probes, "Reset should clear the filter")
+}
+
+func TestBloomFilterSurvivesRebuilds(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](20, 0.1, elastichash.WithBloomFilter(), elastichash.WithAutoGrow())
+	for i := range 500 {
+		mustInsert(t, ht, i, i)
+	}
+	for i := range 400 {
+		require.True(t, ht.Delete(i))
+	}
+	clone := ht.Clone()
+	doubled := elastichash.MapValues(ht, func(v int) int { return v * 2 })
+	mustInsert(t, ht, 1000, 1000)
+	for i := 400; i < 500; i++ {
+		assert.True(t, ht.Contains(i), "key %d", i)
+		assert.True(t, clone.Contains(i), "clone key %d", i)
+		v, ok := doubled.Get(i)
+		assert.True(t, ok, "mapped key %d", i)
+		assert.Equal(t, i*2, v)
+	}
+	assert.False(t, clone.Contains(1000), "the clone's filter should be its own")
+}
+
+func BenchmarkGetMissBloom(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		opts []elastichash.Option
+	}{
+		{"plain", nil},
+		{"bloom", []elastichash.Option{elastichash.WithBloomFilter()}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			ht := elastichash.NewHashTable[int, int](10000, 0.1, bc.opts...)
+			for i := range 8000 {
+				if _, err := ht.Insert(i, i); err != nil {
+					b.Fatal(err)
+				}
+			}
+			probes := 0
+			b.ResetTimer()
+			for i := range b.N {
+				_, _, p := ht.GetWithProbes(-1 - i)
+				probes += p
+			}
+			b.ReportMetric(float64(probes)/float64(b.N), "probes/op")
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package elastichash
+
+// BytesHashTable is a HashTable keyed by byte slices. Slices are not
+// comparable, so keys are stored as strings; the stored copy is unaffected by
+// later writes to the caller's slice.
+type BytesHashTable[V any] struct {
+	ht *HashTable[string, V]
+}
+
+func NewBytesHashTable[V any](capacity int, delta float64) *BytesHashTable[V] {
+	return &BytesHashTable[V]{ht: NewHashTable[string, V](capacity, delta)}
+}
+
+// Insert stores value under key, overwriting any existing value, and reports
+// whether it did overwrite one.
+func (bt *BytesHashTable[V]) Insert(key []byte, value V) (replaced bool, err error) {
+	return bt.ht.Insert(string(key), value)
+}
+
+// Get returns the value stored under key.
+func (bt *BytesHashTable[V]) Get(key []byte) (V, bool) {
+	return bt.ht.Get(string(key))
+}
+
+// Delete removes key and reports whether it was present.
+func (bt *BytesHashTable[V]) Delete(key []byte) bool {
+	return bt.ht.Delete(string(key))
+}
+
+// Len returns the number of live entries.
+func (bt *BytesHashTable[V]) Len() int {
+	return bt.ht.Len()
+}
+
+// Cap returns the configured capacity.
+func (bt *BytesHashTable[V]) Cap() int {
+	return bt.ht.Cap()
+}
+
+func (bt *BytesHashTable[V]) String() string {
+	return bt.ht.String()
+}
@@ -0,0 +1,33 @@
+package elastichash_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestBytesHashTable(t *testing.T) {
+	bt := elastichash.NewBytesHashTable[int](10, 0.1)
+	key := []byte("foo")
+	_, err := bt.Insert(key, 1)
+	require.NoError(t, err)
+
+	// Mutating the caller's slice must not affect the stored key.
+	key[0] = 'g'
+	v, ok := bt.Get([]byte("foo"))
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	_, ok = bt.Get(key)
+	assert.False(t, ok)
+
+	replaced, err := bt.Insert([]byte("foo"), 2)
+	require.NoError(t, err)
+	assert.True(t, replaced)
+	assert.Equal(t, 1, bt.Len())
+
+	assert.True(t, bt.Delete([]byte("foo")))
+	assert.Equal(t, 0, bt.Len())
+}
@@ -0,0 +1,101 @@
+package elastichash
+
+import "sync"
+
+// ConcurrentHashTable is a HashTable that is safe for concurrent use. Writes
+// take an exclusive lock and reads a shared one. It wraps only the core
+// methods, with HashTable's signatures, so code limited to those can switch
+// between the two; the rest of HashTable's API is not available here.
+type ConcurrentHashTable[K comparable, V any] struct {
+	mu sync.RWMutex
+	ht *HashTable[K, V]
+}
+
+func NewConcurrentHashTable[K ValidKey, V any](capacity int, delta float64, opts ...Option) *ConcurrentHashTable[K, V] {
+	return &ConcurrentHashTable[K, V]{ht: NewHashTable[K, V](capacity, delta, opts...)}
+}
+
+// Insert stores value under key and reports whether it replaced an existing
+// value, as HashTable.Insert does.
+func (c *ConcurrentHashTable[K, V]) Insert(key K, value V) (replaced bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ht.Insert(key, value)
+}
+
+func (c *ConcurrentHashTable[K, V]) Update(key K, value V) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ht.Update(key, value)
+}
+
+// CompareAndSwap is HashTable.CompareAndSwap under the write lock, so the
+// comparison and the store are atomic with respect to other callers.
+func (c *ConcurrentHashTable[K, V]) CompareAndSwap(key K, old, new V) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ht.CompareAndSwap(key, old, new)
+}
+
+// CompareAndDelete is HashTable.CompareAndDelete under the write lock.
+func (c *ConcurrentHashTable[K, V]) CompareAndDelete(key K, old V) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ht.CompareAndDelete(key, old)
+}
+
+func (c *ConcurrentHashTable[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ht.Delete(key)
+}
+
+func (c *ConcurrentHashTable[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ht.Get(key)
+}
+
+func (c *ConcurrentHashTable[K, V]) Contains(key K) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ht.Contains(key)
+}
+
+// Len returns the number of live entries. The count is kept atomically, so
+// Len takes no lock and never waits behind a writer.
+func (c *ConcurrentHashTable[K, V]) Len() int {
+	return c.ht.Len()
+}
+
+func (c *ConcurrentHashTable[K, V]) Cap() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ht.Cap()
+}
+
+func (c *ConcurrentHashTable[K, V]) Keys() []K {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ht.Keys()
+}
+
+func (c *ConcurrentHashTable[K, V]) Values() []V {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ht.Values()
+}
+
+// Range calls f for every live entry while holding the read lock, so f must
+// not call any method that writes to the table.
+func (c *ConcurrentHashTable[K, V]) Range(f func(key K, value V) bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	c.ht.Range(f)
+}
+
+func (c *ConcurrentHashTable[K, V]) String() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ht.String()
+}
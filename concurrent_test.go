@@ -0,0 +1,135 @@
+package elastichash_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestConcurrentHashTable(t *testing.T) {
+	const workers, perWorker = 8, 200
+	ct := elastichash.NewConcurrentHashTable[int, int](workers*perWorker*2, 0.1)
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWorker {
+				key := w*perWorker + i
+				_, err := ct.Insert(key, key)
+				assert.NoError(t, err)
+				v, ok := ct.Get(key)
+				assert.True(t, ok)
+				assert.Equal(t, key, v)
+				assert.True(t, ct.Update(key, -key))
+				if i%2 == 0 {
+					assert.True(t, ct.Delete(key))
+				}
+				ct.Contains(key + 1)
+				ct.Len()
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, workers*perWorker/2, ct.Len())
+	ct.Range(func(k, v int) bool {
+		assert.Equal(t, -k, v)
+		assert.Equal(t, 1, k%perWorker%2)
+		return true
+	})
+}
+
+func TestConcurrentInsertReportsReplaced(t *testing.T) {
+	ct := elastichash.NewConcurrentHashTable[string, int](10, 0.1)
+	replaced, err := ct.Insert("a", 1)
+	require.NoError(t, err)
+	assert.False(t, replaced)
+	replaced, err = ct.Insert("a", 2)
+	require.NoError(t, err)
+	assert.True(t, replaced)
+	assert.Equal(t, 1, ct.Len())
+}
+
+// TestConcurrentLenDuringWrites is meant for -race: Len takes no lock, so it
+// must only touch the atomic item count while writers insert, delete and
+// grow the table.
+func TestConcurrentLenDuringWrites(t *testing.T) {
+	const writers, perWriter = 4, 500
+	ct := elastichash.NewConcurrentHashTable[int, int](8, 0.1, elastichash.WithAutoGrow())
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for range 2 {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				n := ct.Len()
+				assert.GreaterOrEqual(t, n, 0)
+				assert.LessOrEqual(t, n, writers*perWriter)
+			}
+		}()
+	}
+
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWriter {
+				key := w*perWriter + i
+				_, err := ct.Insert(key, key)
+				assert.NoError(t, err)
+				if i%3 == 0 {
+					ct.Delete(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	readers.Wait()
+
+	assert.Equal(t, writers*(perWriter-(perWriter+2)/3), ct.Len())
+}
+
+func TestConcurrentCompareAndSwap(t *testing.T) {
+	const workers, increments = 8, 100
+	ct := elastichash.NewConcurrentHashTable[string, int](10, 0.1)
+	_, err := ct.Insert("counter", 0)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range increments {
+				for {
+					v, _ := ct.Get("counter")
+					if ct.CompareAndSwap("counter", v, v+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	v, _ := ct.Get("counter")
+	assert.Equal(t, workers*increments, v, "no increment should be lost")
+	assert.False(t, ct.CompareAndDelete("counter", 0))
+	assert.True(t, ct.CompareAndDelete("counter", v))
+	assert.Zero(t, ct.Len())
+}
@@ -0,0 +1,151 @@
+package elastichash
+
+import (
+	"fmt"
+	"math"
+)
+
+// ToMap copies every live entry into a new builtin map.
+func (ht *HashTable[K, V]) ToMap() map[K]V {
+	m := make(map[K]V, ht.Len())
+	ht.Range(func(key K, value V) bool {
+		m[key] = value
+		return true
+	})
+	return m
+}
+
+// FromMap builds a table holding every entry of m, with the smallest capacity
+// whose MaxLen fits len(m) at the given delta.
+func FromMap[K ValidKey, V any](m map[K]V, delta float64) (*HashTable[K, V], error) {
+	ht, err := TryNewHashTable[K, V](fitCapacity(len(m), delta), delta)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range m {
+		if _, err := ht.Insert(k, v); err != nil {
+			return nil, err
+		}
+	}
+	return ht, nil
+}
+
+// FromSlice builds a table holding entries, sized for len(entries) at the
+// given delta; later entries win over earlier ones with the same key. A tight
+// fit that leaves an entry without a reachable slot grows the table rather
+// than failing, so the only errors are for an invalid delta.
+func FromSlice[K ValidKey, V any](entries []Entry[K, V], delta float64, opts ...Option) (*HashTable[K, V], error) {
+	ht, err := TryNewHashTable[K, V](fitCapacity(len(entries), delta), delta, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ht.fill(entries)
+	return ht, nil
+}
+
+// Filter returns a new table, sized to fit them, holding the entries for
+// which pred returns true. The new table keeps ht's hasher and options; ht
+// itself is unchanged.
+func (ht *HashTable[K, V]) Filter(pred func(K, V) bool) *HashTable[K, V] {
+	var matched []Entry[K, V]
+	ht.Range(func(key K, value V) bool {
+		if pred(key, value) {
+			matched = append(matched, Entry[K, V]{Key: key, Value: value})
+		}
+		return true
+	})
+	return ht.withEntries(matched)
+}
+
+// Partition splits ht's entries over n new tables by key hash modulo n, so
+// a key always lands in the same partition of a table with the same hasher.
+// Each partition is sized for its share and keeps ht's configuration; ht
+// itself is unchanged. It panics if n < 1.
+func (ht *HashTable[K, V]) Partition(n int) []*HashTable[K, V] {
+	if n < 1 {
+		panic(fmt.Sprintf("elastichash: cannot partition into %d tables", n))
+	}
+	groups := make([][]Entry[K, V], n)
+	for _, level := range ht.levels {
+		for _, e := range level {
+			if e.state == occupied {
+				g := e.hash % uint64(n)
+				groups[g] = append(groups[g], Entry[K, V]{Key: e.key, Value: e.value})
+			}
+		}
+	}
+	parts := make([]*HashTable[K, V], n)
+	for i, entries := range groups {
+		parts[i] = ht.withEntries(entries)
+	}
+	return parts
+}
+
+// withEntries returns a table with ht's configuration, sized to fit entries,
+// that holds them; later entries win over earlier ones with the same key.
+func (ht *HashTable[K, V]) withEntries(entries []Entry[K, V]) *HashTable[K, V] {
+	next := ht.emptyCopy(fitCapacity(len(entries), ht.delta))
+	next.fill(entries)
+	return next
+}
+
+// fill inserts entries into ht, growing it whenever an insert fails.
+func (ht *HashTable[K, V]) fill(entries []Entry[K, V]) {
+	for _, p := range entries {
+		// A tight fit can leave an insert without a free slot in reach, in
+		// which case the table grows as WithAutoGrow would.
+		_, _, _, err := ht.insert(p.Key, p.Value)
+		for err != nil {
+			ht.grow()
+			_, _, _, err = ht.insert(p.Key, p.Value)
+		}
+	}
+}
+
+// MapValues returns a new table with ht's keys, configuration and layout in
+// which every value has been passed through f. ht is unchanged.
+func MapValues[K comparable, V, W any](ht *HashTable[K, V], f func(V) W) *HashTable[K, W] {
+	next := &HashTable[K, W]{tableState: tableState[K, W]{
+		capacity:           ht.capacity,
+		delta:              ht.delta,
+		seed:               ht.seed,
+		hasher:             ht.hasher,
+		normalize:          ht.normalize,
+		levels:             make([][]slot[K, W], len(ht.levels)),
+		occupanciesByLevel: append([]int(nil), ht.occupanciesByLevel...),
+		tombstonesByLevel:  append([]int(nil), ht.tombstonesByLevel...),
+		depthsByLevel:      append([]int64(nil), ht.depthsByLevel...),
+		limitsByLevel:      append([]int64(nil), ht.limitsByLevel...),
+		spillDepth:         ht.spillDepth,
+		c:                  ht.c,
+		threshold:          ht.threshold,
+		funnel:             ht.funnel,
+		bloom:              ht.bloom.clone(),
+		opts:               ht.opts,
+	}}
+	next.items.Store(ht.items.Load())
+	for i, level := range ht.levels {
+		next.levels[i] = make([]slot[K, W], len(level))
+		for j, e := range level {
+			if e.state == occupied {
+				next.levels[i][j] = slot[K, W]{key: e.key, value: f(e.value), hash: e.hash, state: occupied}
+			} else {
+				next.levels[i][j].state = e.state
+			}
+		}
+	}
+	return next
+}
+
+// fitCapacity returns the smallest capacity whose MaxLen at delta is at least
+// n, or 1 when delta is out of range and the constructor will reject it.
+func fitCapacity(n int, delta float64) int {
+	if !(delta > 0 && delta < 1) {
+		return 1
+	}
+	capacity := max(1, int(math.Ceil(float64(n)/(1-delta))))
+	for capacity-int(delta*float64(capacity)) < n {
+		capacity++
+	}
+	return capacity
+}
@@ -0,0 +1,150 @@
+package elastichash_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestToMap(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.1)
+	want := map[string]int{}
+	for i := range 50 {
+		key := fmt.Sprintf("key%d", i)
+		mustInsert(t, ht, key, i)
+		want[key] = i
+	}
+	require.True(t, ht.Delete("key0"))
+	delete(want, "key0")
+	assert.Equal(t, want, ht.ToMap())
+}
+
+func TestFromMap(t *testing.T) {
+	m := map[int]string{}
+	for i := range 500 {
+		m[i] = fmt.Sprint(i)
+	}
+	ht, err := elastichash.FromMap(m, 0.1)
+	require.NoError(t, err)
+	assert.Equal(t, len(m), ht.Len())
+	assert.GreaterOrEqual(t, ht.MaxLen(), len(m))
+	assert.Equal(t, m, ht.ToMap())
+
+	empty, err := elastichash.FromMap(map[int]string{}, 0.1)
+	require.NoError(t, err)
+	assert.Zero(t, empty.Len())
+
+	_, err = elastichash.FromMap(m, 1.5)
+	assert.Error(t, err)
+}
+
+func TestFromSlice(t *testing.T) {
+	entries := []elastichash.Entry[string, int]{
+		{Key: "a", Value: 1},
+		{Key: "b", Value: 2},
+		{Key: "a", Value: 3},
+	}
+	ht, err := elastichash.FromSlice(entries, 0.1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 3, "b": 2}, ht.ToMap(), "the last duplicate should win")
+
+	var many []elastichash.Entry[int, int]
+	for i := range 1000 {
+		many = append(many, elastichash.Entry[int, int]{Key: i, Value: -i})
+	}
+	big, err := elastichash.FromSlice(many, 0.05, elastichash.WithC(1))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, many, big.Entries())
+
+	empty, err := elastichash.FromSlice[int, int](nil, 0.1)
+	require.NoError(t, err)
+	assert.Zero(t, empty.Len())
+
+	_, err = elastichash.FromSlice(entries, 0)
+	assert.Error(t, err)
+}
+
+func TestMapRoundTrip(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](1000, 0.2)
+	for i := range 700 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	back, err := elastichash.FromMap(ht.ToMap(), 0.2)
+	require.NoError(t, err)
+	assert.True(t, ht.Equal(back, nil))
+}
+
+func TestFilter(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](200, 0.1)
+	for i := range 100 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	even := ht.Filter(func(_ string, v int) bool { return v%2 == 0 })
+	assert.Equal(t, 50, even.Len())
+	assert.Less(t, even.Cap(), ht.Cap(), "the result is sized to the matches")
+	for i := range 100 {
+		assert.Equal(t, i%2 == 0, even.Contains(fmt.Sprintf("key%d", i)))
+	}
+	assert.Equal(t, 100, ht.Len(), "the source is unchanged")
+
+	none := ht.Filter(func(string, int) bool { return false })
+	assert.Zero(t, none.Len())
+}
+
+func TestPartition(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](1000, 0.1, elastichash.WithC(2))
+	for i := range 800 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	parts := ht.Partition(4)
+	require.Len(t, parts, 4)
+
+	seen := make(map[string]int)
+	for p, part := range parts {
+		assert.Positive(t, part.Len())
+		assert.Less(t, part.Cap(), ht.Cap(), "each partition is sized to its share")
+		assert.Equal(t, 2.0, part.C())
+		part.Range(func(key string, value int) bool {
+			_, dup := seen[key]
+			assert.False(t, dup, "%s is in more than one partition", key)
+			seen[key] = value
+			assert.Equal(t, p, int(elastichash.HashKey(key)%4), "%s is in the wrong partition", key)
+			return true
+		})
+	}
+	assert.Equal(t, ht.ToMap(), seen)
+	assert.Equal(t, 800, ht.Len(), "the source is unchanged")
+
+	whole := ht.Partition(1)
+	require.Len(t, whole, 1)
+	assert.True(t, ht.Equal(whole[0], nil))
+	assert.Panics(t, func() { ht.Partition(0) })
+}
+
+func TestMapValues(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.2, elastichash.WithC(2))
+	for i := range 60 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	require.True(t, ht.Delete("key0"))
+
+	strs := elastichash.MapValues(ht, strconv.Itoa)
+	assert.Equal(t, ht.Len(), strs.Len())
+	assert.Equal(t, ht.Cap(), strs.Cap())
+	assert.Equal(t, ht.Stats().Levels, strs.Stats().Levels, "the layout carries over")
+	assert.Equal(t, 2.0, strs.Stats().C)
+	ht.Range(func(k string, v int) bool {
+		s, ok := strs.Get(k)
+		assert.True(t, ok)
+		assert.Equal(t, strconv.Itoa(v), s)
+		return true
+	})
+
+	mustInsert(t, strs, "key0", "zero")
+	assert.False(t, ht.Contains("key0"), "the source is unchanged")
+}
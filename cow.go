@@ -0,0 +1,50 @@
+package elastichash
+
+import "slices"
+
+// COWClone returns a copy of ht that shares its levels with ht until one of
+// the two writes to them. The first write to a shared level, from either
+// table, copies just that level, so a clone that is mostly read costs little
+// more than the per-level counters. Like Clone, the copy is not frozen.
+// Neither table is safe to use concurrently with the other, even for reads
+// on one and writes on the other.
+func (ht *HashTable[K, V]) COWClone() *HashTable[K, V] {
+	if ht.sharedLevels == nil {
+		ht.sharedLevels = make([]bool, len(ht.levels))
+	}
+	for i := range ht.sharedLevels {
+		ht.sharedLevels[i] = true
+	}
+	ht.sharedBloom = ht.bloom != nil
+
+	c := &HashTable[K, V]{tableState: ht.tableState}
+	c.items.Store(ht.items.Load())
+	c.levels = slices.Clone(ht.levels)
+	c.sharedLevels = slices.Clone(ht.sharedLevels)
+	c.occupanciesByLevel = slices.Clone(ht.occupanciesByLevel)
+	c.tombstonesByLevel = slices.Clone(ht.tombstonesByLevel)
+	c.depthsByLevel = slices.Clone(ht.depthsByLevel)
+	c.limitsByLevel = slices.Clone(ht.limitsByLevel)
+	c.frozen = false
+	return c
+}
+
+// writable returns slot idx of level i for modification, first copying the
+// level if a COWClone may still share it. When one table copies a level the
+// other still marks it shared, so that one makes a spare copy of its own on
+// its next write there.
+func (ht *HashTable[K, V]) writable(i, idx int) *slot[K, V] {
+	if ht.sharedLevels != nil && ht.sharedLevels[i] {
+		ht.levels[i] = slices.Clone(ht.levels[i])
+		ht.sharedLevels[i] = false
+	}
+	return &ht.levels[i][idx]
+}
+
+// ownBloom copies the Bloom filter if a COWClone may still share it.
+func (ht *HashTable[K, V]) ownBloom() {
+	if ht.sharedBloom {
+		ht.bloom = ht.bloom.clone()
+		ht.sharedBloom = false
+	}
+}
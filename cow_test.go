@@ -0,0 +1,79 @@
+package elastichash_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestCOWClone(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](200, 0.1, elastichash.WithBloomFilter())
+	for i := range 150 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	levels := len(ht.Stats().Levels)
+
+	clone := ht.COWClone()
+	for i := range levels {
+		assert.True(t, clone.SharesLevel(ht, i), "level %d should start shared", i)
+	}
+	assert.True(t, ht.Equal(clone, nil))
+
+	i, _, ok := clone.Find("key7")
+	require.True(t, ok)
+	require.True(t, clone.Update("key7", -7))
+	assert.False(t, clone.SharesLevel(ht, i), "the written level should be copied")
+	for j := range levels {
+		if j != i {
+			assert.True(t, clone.SharesLevel(ht, j), "level %d was not written", j)
+		}
+	}
+	assert.Equal(t, 7, ht.MustGet("key7"), "the source should not see the clone's write")
+	assert.Equal(t, -7, clone.MustGet("key7"))
+
+	require.True(t, clone.Delete("key8"))
+	mustInsert(t, clone, "clone only", 1)
+	assert.True(t, ht.Contains("key8"))
+	assert.False(t, ht.Contains("clone only"))
+	assert.Equal(t, 150, ht.Len())
+	assert.Equal(t, 150, clone.Len())
+
+	require.True(t, ht.Update("key9", 90))
+	assert.Equal(t, 9, clone.MustGet("key9"), "the clone should not see the source's write")
+
+	ht.Apply(func(_ string, v int) int { return v + 1000 })
+	assert.Equal(t, 1001, ht.MustGet("key1"))
+	assert.Equal(t, 1, clone.MustGet("key1"))
+	for j := range levels {
+		assert.False(t, clone.SharesLevel(ht, j), "Apply writes every level")
+	}
+}
+
+func TestCOWCloneRebuild(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](20, 0.1, elastichash.WithAutoGrow())
+	for i := range 15 {
+		mustInsert(t, ht, i, i)
+	}
+	clone := ht.COWClone()
+	for i := 15; i < 100; i++ {
+		mustInsert(t, clone, i, i)
+	}
+	assert.Equal(t, 100, clone.Len())
+	assert.Equal(t, 15, ht.Len())
+	assert.Equal(t, 20, ht.Cap())
+	for i := range 100 {
+		assert.Equal(t, i < 15, ht.Contains(i), "key %d", i)
+	}
+
+	again := ht.COWClone()
+	deep := ht.Clone()
+	require.True(t, deep.Delete(3))
+	require.True(t, again.Delete(4))
+	assert.True(t, ht.Contains(3))
+	assert.True(t, ht.Contains(4))
+	assert.False(t, deep.SharesLevel(ht, 0), "Clone never shares")
+}
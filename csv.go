@@ -0,0 +1,47 @@
+package elastichash
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// WriteCSV writes every entry of ht to w as a two-column key,value CSV
+// record, in unspecified order. Go methods cannot be limited to string
+// tables, so this and ReadCSV are functions.
+func WriteCSV[K ~string, V ~string](w io.Writer, ht *HashTable[K, V]) error {
+	cw := csv.NewWriter(w)
+	var err error
+	ht.Range(func(key K, value V) bool {
+		err = cw.Write([]string{string(key), string(value)})
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV inserts every key,value record read from r into ht, with later
+// records winning over earlier ones for the same key. Every record must have
+// exactly two fields. It stops at the first malformed record or failed
+// insert and leaves the records read so far in place.
+func ReadCSV[K ~string, V ~string](r io.Reader, ht *HashTable[K, V]) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.ReuseRecord = true
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := ht.Insert(K(record[0]), V(record[1])); err != nil {
+			line, _ := cr.FieldPos(0)
+			return fmt.Errorf("elastichash: csv line %d: %w", line, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package elastichash_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	ht := elastichash.NewHashTable[string, string](20, 0.1)
+	mustInsert(t, ht, "plain", "value")
+	mustInsert(t, ht, "with,comma", "a, b, c")
+	mustInsert(t, ht, `with "quotes"`, "line one\nline two")
+	mustInsert(t, ht, "", "empty key")
+
+	var buf bytes.Buffer
+	require.NoError(t, elastichash.WriteCSV(&buf, ht))
+
+	loaded := elastichash.NewHashTable[string, string](20, 0.1)
+	require.NoError(t, elastichash.ReadCSV(&buf, loaded))
+	assert.True(t, ht.Equal(loaded, nil))
+}
+
+func TestReadCSV(t *testing.T) {
+	type name string
+	ht := elastichash.NewHashTable[name, string](10, 0.1)
+	require.NoError(t, elastichash.ReadCSV(strings.NewReader("a,1\nb,2\na,3\n"), ht))
+	assert.Equal(t, 2, ht.Len())
+	v, _ := ht.Get("a")
+	assert.Equal(t, "3", v, "later rows should win")
+
+	assert.Error(t, elastichash.ReadCSV(strings.NewReader("c,1\nd,2,extra\n"), ht))
+	assert.True(t, ht.Contains("c"), "rows before the bad one should stay")
+
+	full := elastichash.NewHashTable[string, string](2, 0.5)
+	err := elastichash.ReadCSV(strings.NewReader("a,1\nb,2\nc,3\n"), full)
+	assert.ErrorIs(t, err, elastichash.OutOfSpaceErr)
+	assert.ErrorContains(t, err, "csv line")
+}
@@ -0,0 +1,48 @@
+package elastichash
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GoString renders the table's level structure for %#v: one line per level
+// with its size and occupancy, followed by the occupied slots and their keys.
+func (ht *HashTable[K, V]) GoString() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%T{capacity: %d, delta: %v, items: %d,\n", ht, ht.capacity, ht.delta, ht.Len())
+	for i, level := range ht.levels {
+		fmt.Fprintf(&sb, "\tlevel %d: size %d, occupancy %d, tombstones %d\n",
+			i, len(level), ht.occupanciesByLevel[i], ht.tombstonesByLevel[i])
+		for j, e := range level {
+			if e.state == occupied {
+				fmt.Fprintf(&sb, "\t\t[%d] %#v: %#v\n", j, e.key, e.value)
+			}
+		}
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// Dump writes one row per level to w, marking each slot '#' when occupied,
+// 'x' when tombstoned and '.' when empty, then a summary line. Clusters of
+// marks show where probe sequences pile up. Write errors are ignored.
+func (ht *HashTable[K, V]) Dump(w io.Writer) {
+	var row []byte
+	for i, level := range ht.levels {
+		row = row[:0]
+		for _, e := range level {
+			switch e.state {
+			case occupied:
+				row = append(row, '#')
+			case tombstone:
+				row = append(row, 'x')
+			default:
+				row = append(row, '.')
+			}
+		}
+		fmt.Fprintf(w, "%2d %s\n", i, row)
+	}
+	fmt.Fprintf(w, "%d of %d slots used across %d levels, %d tombstones\n",
+		ht.Len(), ht.capacity, len(ht.levels), ht.tombstones())
+}
@@ -0,0 +1,52 @@
+package elastichash_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestGoString(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](20, 0.1)
+	for i := range 10 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	require.True(t, ht.Delete("key0"))
+
+	dump := fmt.Sprintf("%#v", ht)
+	assert.Contains(t, dump, "items: 9")
+	assert.Contains(t, dump, `"key5": 5`)
+	assert.NotContains(t, dump, `"key0"`)
+	for i, l := range ht.Stats().Levels {
+		assert.Contains(t, dump, fmt.Sprintf("level %d: size %d, occupancy %d, tombstones %d", i, l.Size, l.Occupancy, l.Tombstones))
+	}
+}
+
+func TestDump(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](100, 0.1)
+	for i := range 60 {
+		mustInsert(t, ht, i, i)
+	}
+	for i := range 5 {
+		require.True(t, ht.Delete(i))
+	}
+
+	var buf bytes.Buffer
+	ht.Dump(&buf)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	levels := ht.Stats().Levels
+	require.Len(t, lines, len(levels)+1)
+	for i, l := range levels {
+		row := strings.Fields(lines[i])[1]
+		assert.Len(t, row, l.Size)
+		assert.Equal(t, l.Occupancy, strings.Count(row, "#"), "level %d", i)
+		assert.Equal(t, l.Tombstones, strings.Count(row, "x"), "level %d", i)
+	}
+	assert.Equal(t, fmt.Sprintf("55 of 100 slots used across %d levels, 5 tombstones", len(levels)), lines[len(levels)])
+}
@@ -0,0 +1,53 @@
+package elastichash
+
+import "time"
+
+// Tombstones returns the number of tombstoned slots across all levels.
+func (ht *HashTable[K, V]) Tombstones() int {
+	return ht.tombstones()
+}
+
+// LevelOccupancies returns a copy of the per-level occupancy counters.
+func (ht *HashTable[K, V]) LevelOccupancies() []int {
+	return append([]int(nil), ht.occupanciesByLevel...)
+}
+
+// MaxLen exposes the number of items the table accepts before reporting
+// OutOfSpaceErr.
+func (ht *HashTable[K, V]) MaxLen() int {
+	return ht.maxLen()
+}
+
+// ProbeLimits returns each level's probe limit as cached and as computed from
+// scratch.
+func (ht *HashTable[K, V]) ProbeLimits() (cached, fresh []int64) {
+	for i := range ht.levels {
+		cached = append(cached, ht.probeLimit(i))
+		fresh = append(fresh, ht.computeProbeLimit(i))
+	}
+	return cached, fresh
+}
+
+// SetClock replaces the time source a TTLHashTable reads expiries against.
+func (tt *TTLHashTable[K, V]) SetClock(now func() time.Time) {
+	tt.now = now
+}
+
+// SharesLevel reports whether ht and other use the same backing array for
+// level i.
+func (ht *HashTable[K, V]) SharesLevel(other *HashTable[K, V], i int) bool {
+	return len(ht.levels[i]) > 0 && &ht.levels[i][0] == &other.levels[i][0]
+}
+
+// Find returns the level and slot index holding key.
+func (ht *HashTable[K, V]) Find(key K) (int, int, bool) {
+	return ht.find(key)
+}
+
+// SetMaxSerializedCapacity changes the largest capacity the encoders write
+// and the decoders accept, and returns a function that restores it.
+func SetMaxSerializedCapacity(n int) (restore func()) {
+	old := maxSerializedCapacity
+	maxSerializedCapacity = n
+	return func() { maxSerializedCapacity = old }
+}
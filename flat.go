@@ -0,0 +1,36 @@
+package elastichash
+
+import "fmt"
+
+// Exported is a codec-neutral form of a table: its capacity, delta and live
+// entries, ready to be copied into a generated protobuf message or any other
+// wire type. Hasher, seed and options are process-local and not included.
+type Exported[K comparable, V any] struct {
+	Capacity int
+	Delta    float64
+	Entries  []Entry[K, V]
+}
+
+// Export returns ht's capacity, delta and live entries in unspecified order.
+func (ht *HashTable[K, V]) Export() Exported[K, V] {
+	return Exported[K, V]{Capacity: ht.capacity, Delta: ht.delta, Entries: ht.Entries()}
+}
+
+// Import replaces ht's contents with x, re-deriving the levels from
+// x.Capacity and reinserting every entry, with later entries winning over
+// earlier ones for the same key. As with UnmarshalJSON, a zero HashTable can
+// be the target and an existing table keeps its hasher and options. Unlike
+// the decoders, Import accepts any capacity. On error ht is left as it was.
+func (ht *HashTable[K, V]) Import(x Exported[K, V]) error {
+	next, err := ht.importTarget(x.Capacity, x.Delta)
+	if err != nil {
+		return err
+	}
+	for _, e := range x.Entries {
+		if _, _, _, err := next.insert(e.Key, e.Value); err != nil {
+			return fmt.Errorf("elastichash: restoring key %v: %w", e.Key, err)
+		}
+	}
+	ht.adopt(next)
+	return nil
+}
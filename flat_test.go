@@ -0,0 +1,66 @@
+package elastichash_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.2)
+	for i := range 60 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	require.True(t, ht.Delete("key0"))
+
+	x := ht.Export()
+	assert.Equal(t, 100, x.Capacity)
+	assert.Equal(t, 0.2, x.Delta)
+	assert.Len(t, x.Entries, 59)
+
+	var loaded elastichash.HashTable[string, int]
+	require.NoError(t, loaded.Import(x))
+	assert.Equal(t, 100, loaded.Cap())
+	assert.Equal(t, 0.2, loaded.Delta())
+	assert.True(t, ht.Equal(&loaded, nil))
+
+	existing := elastichash.NewHashTable[string, int](10, 0.1, elastichash.WithC(2))
+	mustInsert(t, existing, "stale", 1)
+	require.NoError(t, existing.Import(x))
+	assert.False(t, existing.Contains("stale"), "Import replaces the contents")
+	assert.Equal(t, 2.0, existing.C(), "the target keeps its options")
+	assert.True(t, ht.Equal(existing, nil))
+}
+
+func TestImportErrors(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](10, 0.1)
+	mustInsert(t, ht, 1, 1)
+
+	assert.ErrorContains(t, ht.Import(elastichash.Exported[int, int]{Capacity: 0, Delta: 0.1}), "capacity")
+	tooMany := elastichash.Exported[int, int]{Capacity: 2, Delta: 0.5}
+	for i := range 3 {
+		tooMany.Entries = append(tooMany.Entries, elastichash.Entry[int, int]{Key: i, Value: i})
+	}
+	assert.ErrorIs(t, ht.Import(tooMany), elastichash.OutOfSpaceErr)
+	assert.True(t, ht.Contains(1), "a failed Import leaves the table as it was")
+
+	dup := elastichash.Exported[int, int]{Capacity: 10, Delta: 0.1, Entries: []elastichash.Entry[int, int]{{Key: 1, Value: 1}, {Key: 1, Value: 2}}}
+	require.NoError(t, ht.Import(dup))
+	assert.Equal(t, 2, ht.MustGet(1))
+}
+
+func TestImportIgnoresSerializedLimit(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.1)
+	mustInsert(t, ht, "key", 1)
+	restore := elastichash.SetMaxSerializedCapacity(64)
+	defer restore()
+
+	var imported elastichash.HashTable[string, int]
+	require.NoError(t, imported.Import(ht.Export()))
+	assert.Equal(t, 100, imported.Cap())
+	assert.Equal(t, 1, imported.MustGet("key"))
+}
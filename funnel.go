@@ -0,0 +1,71 @@
+package elastichash
+
+import "math"
+
+// NewFunnelHashTable returns a table that places keys by funnel hashing, the
+// companion scheme to elastic hashing from the same paper. Each level is cut
+// into buckets of a fixed width; Insert tries the one bucket the key hashes to
+// on each level in turn and falls through to the next level only when that
+// bucket is full, with a linear sweep of the last level as the final
+// fallback. The table otherwise behaves like one from NewHashTable, and the
+// elastic-only WithC and WithThreshold settings have no effect.
+func NewFunnelHashTable[K ValidKey, V any](capacity int, delta float64, opts ...Option) *HashTable[K, V] {
+	ht := NewHashTable[K, V](capacity, delta, opts...)
+	ht.funnel = true
+	return ht
+}
+
+// bucketWidth is the number of slots in a funnel bucket, which the paper
+// sets to Θ(log 1/δ).
+func (ht *HashTable[K, V]) bucketWidth() int {
+	return max(1, int(math.Ceil(2*math.Log2(1/ht.delta))))
+}
+
+// bucketStart returns the first slot of the bucket h selects on level i. A
+// level narrower than two buckets is a single bucket.
+func (ht *HashTable[K, V]) bucketStart(h uint64, i int) (start, width int) {
+	size := len(ht.levels[i])
+	width = min(ht.bucketWidth(), size)
+	buckets := size / width
+	folded := h ^ h>>32
+	return int(folded%uint64(buckets)) * width, width
+}
+
+func (ht *HashTable[K, V]) funnelInsert(h uint64, key K, value V, probes int) (bool, int, int, error) {
+	for i := range ht.levels {
+		start, width := ht.bucketStart(h, i)
+		for j := range width {
+			probes++
+			if ht.claim(i, start+j, h, key, value) {
+				return false, i, probes, nil
+			}
+		}
+	}
+	last := len(ht.levels) - 1
+	placed, n := ht.spill(h, key, value)
+	if probes += n; placed {
+		return false, last, probes, nil
+	}
+	return false, -1, probes, &InsertError{
+		Err:        FailedToInsertErr,
+		Items:      ht.Len(),
+		MaxLen:     ht.maxLen(),
+		Level:      last,
+		Occupancy:  ht.occupanciesByLevel[last],
+		ProbeLimit: int64(ht.bucketWidth()),
+	}
+}
+
+func (ht *HashTable[K, V]) funnelLookup(h uint64, key K) (int, int, int, bool) {
+	probes := 0
+	for i, level := range ht.levels {
+		start, width := ht.bucketStart(h, i)
+		for j := range width {
+			probes++
+			if e := &level[start+j]; e.state == occupied && e.hash == h && e.key == key {
+				return i, start + j, probes, true
+			}
+		}
+	}
+	return ht.lookupSpill(h, key, probes)
+}
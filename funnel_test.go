@@ -0,0 +1,94 @@
+package elastichash_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestFunnelHashTable(t *testing.T) {
+	ht := elastichash.NewFunnelHashTable[string, int](1000, 0.1)
+	for i := range 500 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	for i := range 500 {
+		v, ok := ht.Get(fmt.Sprintf("key%d", i))
+		require.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	assert.False(t, ht.Contains("missing"))
+
+	for i := range 250 {
+		require.True(t, ht.Delete(fmt.Sprintf("key%d", i)))
+	}
+	for i := range 250 {
+		assert.False(t, ht.Contains(fmt.Sprintf("key%d", i)))
+		mustInsert(t, ht, fmt.Sprintf("again%d", i), i)
+	}
+	assert.Equal(t, 500, ht.Len())
+
+	replaced, err := ht.Insert("again0", -1)
+	require.NoError(t, err)
+	assert.True(t, replaced)
+	assert.Equal(t, -1, ht.MustGet("again0"))
+}
+
+func TestFunnelFillToMaxLen(t *testing.T) {
+	for _, capacity := range []int{1, 3, 10, 100, 1000} {
+		t.Run(fmt.Sprintf("capacity %d", capacity), func(t *testing.T) {
+			ht := elastichash.NewFunnelHashTable[int, int](capacity, 0.1)
+			for i := range ht.MaxLen() {
+				mustInsert(t, ht, i, i)
+			}
+			_, err := ht.Insert(-1, -1)
+			assert.ErrorIs(t, err, elastichash.OutOfSpaceErr)
+			for i := range ht.MaxLen() {
+				assert.True(t, ht.Contains(i))
+			}
+		})
+	}
+}
+
+func TestFunnelAutoGrow(t *testing.T) {
+	ht := elastichash.NewFunnelHashTable[int, int](4, 0.1, elastichash.WithAutoGrow())
+	for i := range 1000 {
+		mustInsert(t, ht, i, i)
+	}
+	assert.Equal(t, 1000, ht.Len())
+	assert.Equal(t, 999, ht.MustGet(999))
+}
+
+// BenchmarkFillToMaxLen fills tables of both kinds to MaxLen and reports the
+// fraction of inserts that failed on the way and the mean probes per insert.
+func BenchmarkFillToMaxLen(b *testing.B) {
+	variants := map[string]func() *elastichash.HashTable[int, int]{
+		"elastic": func() *elastichash.HashTable[int, int] {
+			return elastichash.NewHashTable[int, int](10_000, 0.05, elastichash.WithC(1))
+		},
+		"funnel": func() *elastichash.HashTable[int, int] {
+			return elastichash.NewFunnelHashTable[int, int](10_000, 0.05)
+		},
+	}
+	for _, name := range []string{"elastic", "funnel"} {
+		b.Run(name, func(b *testing.B) {
+			attempts, failures, probes := 0, 0, 0
+			for range b.N {
+				ht := variants[name]()
+				for i := 0; ht.Len() < ht.MaxLen() && i < 2*ht.Cap(); i++ {
+					attempts++
+					_, n, err := ht.InsertWithProbes(i, i)
+					probes += n
+					if err != nil {
+						failures++
+					}
+				}
+			}
+			b.ReportMetric(float64(failures)/float64(attempts), "failed/insert")
+			b.ReportMetric(float64(probes)/float64(attempts), "probes/insert")
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package elastichash_test
+
+import (
+	"errors"
+	"testing"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+// FuzzHashTable reads its input as (op, key) byte pairs and replays them
+// against a HashTable and a map, checking they agree after every step. The
+// first byte picks between a small auto-growing table, which exercises
+// resizes, and a fixed one that the input can fill to MaxLen.
+func FuzzHashTable(f *testing.F) {
+	f.Add([]byte{0, 0, 1, 0, 2, 0, 1, 1})
+	grow := []byte{1}
+	for i := range 64 {
+		grow = append(grow, 0, byte(i))
+	}
+	f.Add(grow)
+	full := []byte{0}
+	for i := range 40 {
+		full = append(full, 0, byte(i*7))
+	}
+	for i := range 20 {
+		full = append(full, 1, byte(i*7), 0, byte(200+i))
+	}
+	f.Add(full)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) == 0 {
+			return
+		}
+		var ht *elastichash.HashTable[uint8, int]
+		if data[0]%2 == 1 {
+			ht = elastichash.NewHashTable[uint8, int](4, 0.1, elastichash.WithAutoGrow())
+		} else {
+			ht = elastichash.NewHashTable[uint8, int](40, 0.1)
+		}
+		want := map[uint8]int{}
+		for i := 1; i+1 < len(data); i += 2 {
+			key := data[i+1]
+			switch data[i] % 3 {
+			case 0:
+				_, err := ht.Insert(key, i)
+				switch {
+				case err == nil:
+					want[key] = i
+				case errors.Is(err, elastichash.OutOfSpaceErr), errors.Is(err, elastichash.FailedToInsertErr):
+					if _, ok := want[key]; ok {
+						t.Fatalf("step %d: replacing key %d failed: %v", i, key, err)
+					}
+				default:
+					t.Fatalf("step %d: insert %d: %v", i, key, err)
+				}
+			case 1:
+				_, existed := want[key]
+				if got := ht.Delete(key); got != existed {
+					t.Fatalf("step %d: Delete(%d) = %v, want %v", i, key, got, existed)
+				}
+				delete(want, key)
+			case 2:
+				// A plain read; the full comparison below covers it.
+			}
+			if ht.Len() != len(want) {
+				t.Fatalf("step %d: Len() = %d, want %d", i, ht.Len(), len(want))
+			}
+			for k := range 256 {
+				got, ok := ht.Get(uint8(k))
+				w, wok := want[uint8(k)]
+				if ok != wok || got != w {
+					t.Fatalf("step %d: Get(%d) = %d, %v, want %d, %v", i, k, got, ok, w, wok)
+				}
+			}
+		}
+	})
+}
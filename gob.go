@@ -0,0 +1,63 @@
+package elastichash
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+)
+
+// gobTable is the gob form of a HashTable: its configuration and the live
+// entries flattened into parallel slices.
+type gobTable[K comparable, V any] struct {
+	Capacity int
+	Delta    float64
+	Keys     []K
+	Values   []V
+}
+
+// GobEncode encodes the table's capacity, delta and live entries, under the
+// same capacity limit as MarshalJSON.
+func (ht *HashTable[K, V]) GobEncode() ([]byte, error) {
+	if err := ht.checkEncodable(); err != nil {
+		return nil, err
+	}
+	gt := gobTable[K, V]{
+		Capacity: ht.capacity,
+		Delta:    ht.delta,
+		Keys:     make([]K, 0, ht.Len()),
+		Values:   make([]V, 0, ht.Len()),
+	}
+	ht.Range(func(key K, value V) bool {
+		gt.Keys = append(gt.Keys, key)
+		gt.Values = append(gt.Values, value)
+		return true
+	})
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(gt); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode rebuilds the table from GobEncode output, following the same
+// rules as UnmarshalJSON.
+func (ht *HashTable[K, V]) GobDecode(data []byte) error {
+	var gt gobTable[K, V]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&gt); err != nil {
+		return err
+	}
+	if len(gt.Keys) != len(gt.Values) {
+		return fmt.Errorf("elastichash: gob data has %d keys but %d values", len(gt.Keys), len(gt.Values))
+	}
+	next, err := ht.decodeTarget(gt.Capacity, gt.Delta)
+	if err != nil {
+		return err
+	}
+	for i, key := range gt.Keys {
+		if _, _, _, err := next.insert(key, gt.Values[i]); err != nil {
+			return fmt.Errorf("elastichash: restoring key %v: %w", key, err)
+		}
+	}
+	ht.adopt(next)
+	return nil
+}
@@ -0,0 +1,41 @@
+package elastichash_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestGobRoundTrip(t *testing.T) {
+	ht := elastichash.NewHashTable[string, float64](100, 0.1)
+	for i := range 60 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), float64(i)/2)
+	}
+	require.True(t, ht.Delete("key3"))
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(ht))
+
+	var loaded elastichash.HashTable[string, float64]
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&loaded))
+	assert.Equal(t, ht.Len(), loaded.Len())
+	assert.Equal(t, ht.Cap(), loaded.Cap())
+	ht.Range(func(k string, v float64) bool {
+		got, ok := loaded.Get(k)
+		assert.True(t, ok, "key %s should survive the round trip", k)
+		assert.Equal(t, v, got)
+		return true
+	})
+	assert.False(t, loaded.Contains("key3"))
+}
+
+func TestGobDecodeGarbage(t *testing.T) {
+	var ht elastichash.HashTable[string, int]
+	assert.Error(t, ht.GobDecode([]byte("not gob")))
+}
@@ -1,67 +1,340 @@
 package elastichash
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"hash/maphash"
+	"iter"
 	"math"
-	"strconv"
+	"reflect"
 	"strings"
+	"sync/atomic"
 )
 
 var (
 	OutOfSpaceErr     = errors.New("out of space, hash table is full")
 	FailedToInsertErr = errors.New("failed to insert to hash table")
+	FrozenErr         = errors.New("hash table is frozen")
 )
 
+// InsertError wraps OutOfSpaceErr or FailedToInsertErr with the state of the
+// table when Insert gave up. Level is the last level Insert tried, or -1 when
+// the table was already at MaxLen and no level was probed.
+type InsertError struct {
+	Err        error
+	Items      int
+	MaxLen     int
+	Level      int
+	Occupancy  int
+	ProbeLimit int64
+}
+
+func (e *InsertError) Error() string {
+	if e.Level < 0 {
+		return fmt.Sprintf("%v (%d of %d items)", e.Err, e.Items, e.MaxLen)
+	}
+	return fmt.Sprintf("%v (%d of %d items, level %d holds %d with probe limit %d)",
+		e.Err, e.Items, e.MaxLen, e.Level, e.Occupancy, e.ProbeLimit)
+}
+
+func (e *InsertError) Unwrap() error {
+	return e.Err
+}
+
 const (
-	threshold = 0.25
+	defaultThreshold = 0.25
+	defaultDelta     = 0.1
 )
 
+// hashSeed is shared by every call to HashKey. A zero maphash.Hash picks a
+// fresh random seed on first use, which would send Insert and Get for the
+// same key to different slots.
+var hashSeed = maphash.MakeSeed()
+
+// ValidKey lists the key types HashKey knows how to hash. Byte slices are not
+// comparable and so cannot be keys directly; use BytesHashTable for those.
+// Float keys follow ==: -0.0 and +0.0 are the same key, and NaN never matches
+// itself, so a NaN key can be inserted but never found again.
 type ValidKey interface {
 	comparable
-	~[]byte | ~int | ~string
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 |
+		~string
 }
 
 func HashKey[K ValidKey](k K) uint64 {
-	var h maphash.Hash
+	return hashKey(hashSeed, k)
+}
+
+func hashKey[K comparable](seed maphash.Seed, k K) uint64 {
 	switch v := any(k).(type) {
-	case ([]byte):
-		h.Write(v)
 	case (int):
-		h.WriteString(strconv.Itoa(v))
+		return hashUint(seed, uint64(v))
+	case (int64):
+		return hashUint(seed, uint64(v))
+	case (uint64):
+		return hashUint(seed, v)
+	case (float64):
+		return hashFloat(seed, v)
 	case (string):
-		h.WriteString(v)
+		return maphash.String(seed, v)
+	}
+	// Narrower widths and named key types such as `type ID uint32` miss the
+	// cases above; hash them by their underlying kind.
+	rv := reflect.ValueOf(k)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return hashUint(seed, uint64(rv.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return hashUint(seed, rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return hashFloat(seed, rv.Float())
+	case reflect.String:
+		return maphash.String(seed, rv.String())
+	}
+	return maphash.String(seed, "")
+}
+
+// hashUint hashes the eight little-endian bytes of u. Signed keys are
+// widened to int64 first, so a key hashes the same whatever its width. The
+// one-shot maphash functions avoid setting up a maphash.Hash per call.
+func hashUint(seed maphash.Seed, u uint64) uint64 {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], u)
+	return maphash.Bytes(seed, b[:])
+}
+
+// hashFloat hashes the IEEE bits of f. -0.0 == +0.0, so both must hash alike.
+func hashFloat(seed maphash.Seed, f float64) uint64 {
+	if f == 0 {
+		f = 0
+	}
+	return hashUint(seed, math.Float64bits(f))
+}
+
+// hashable reports whether HashKey supports K, i.e. whether a table keyed by
+// K can work without a caller-supplied hasher.
+func hashable[K comparable]() bool {
+	switch reflect.TypeFor[K]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return true
 	}
-	return h.Sum64()
+	return false
+}
+
+// Hashable is implemented by key types that compute their own hash, such as
+// small structs used as composite keys. Keys must still be comparable so
+// slots can be matched with ==.
+type Hashable interface {
+	HashCode() uint64
+}
+
+// Entry is a key and its value, as returned by Entries and passed to
+// InsertMany.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
 }
 
-type entry[K ValidKey, V any] struct {
+// slotState records whether a slot has never been used, holds a live entry
+// or held one that was deleted.
+type slotState uint8
+
+const (
+	empty slotState = iota
+	occupied
+	tombstone
+)
+
+// slot is stored by value in its level so that filling a table does not
+// allocate per key; state tells empty and tombstoned slots apart. hash caches
+// the key's hash so rehashing skips the hasher and lookups can reject most
+// mismatches without comparing keys.
+type slot[K comparable, V any] struct {
 	key   K
 	value V
+	hash  uint64
+	state slotState
 }
 
-type HashTable[K ValidKey, V any] struct {
-	capacity int
-	delta    float64
+type HashTable[K comparable, V any] struct {
+	// items is atomic so Len needs no lock, even under ConcurrentHashTable's
+	// writers. Everything else lives in tableState, which rebuilds and
+	// decoders replace wholesale through adopt.
+	items atomic.Int64
+	tableState[K, V]
+}
+
+type tableState[K comparable, V any] struct {
+	capacity  int
+	delta     float64
+	seed      maphash.Seed
+	hasher    func(K) uint64
+	normalize func(K) K
 
-	items              int
-	levels             [][]*entry[K, V]
+	levels             [][]slot[K, V]
 	occupanciesByLevel []int
+	tombstonesByLevel  []int
+	depthsByLevel      []int64
+	limitsByLevel      []int64
+	spillDepth         int
 	c                  float64
+	threshold          float64
+	resizes            int
+	funnel             bool
+	maxProbes          int
+	bloom              *bloomFilter
+	frozen             bool
+	// sharedLevels marks the levels, and sharedBloom the Bloom filter, that
+	// a COWClone may still be reading; writable copies them before the first
+	// write. sharedLevels is nil until the table is first COWCloned.
+	sharedLevels []bool
+	sharedBloom  bool
+
+	opts options
+}
+
+// NewHashTable returns an empty table that holds up to capacity entries while
+// keeping a delta fraction of its slots free. It panics on invalid arguments;
+// use TryNewHashTable to get an error instead.
+func NewHashTable[K ValidKey, V any](capacity int, delta float64, opts ...Option) *HashTable[K, V] {
+	return newHashTable[K, V](capacity, delta, hashSeed, nil, opts)
+}
+
+// TryNewHashTable is NewHashTable that reports a capacity below 1, a delta
+// outside (0, 1) or an invalid option as an error rather than panicking.
+func TryNewHashTable[K ValidKey, V any](capacity int, delta float64, opts ...Option) (*HashTable[K, V], error) {
+	return tryNewHashTable[K, V](capacity, delta, hashSeed, nil, opts)
+}
+
+// NewHashTableWithSeed is NewHashTable with an explicit hash seed. Tables
+// built from the same seed and fed the same inserts end up with identical
+// layouts, which keeps tests reproducible and lets processes agree on how
+// keys hash. A maphash.Seed cannot outlive the process that made it.
+func NewHashTableWithSeed[K ValidKey, V any](capacity int, delta float64, seed maphash.Seed, opts ...Option) *HashTable[K, V] {
+	return newHashTable[K, V](capacity, delta, seed, nil, opts)
 }
 
-func NewHashTable[K ValidKey, V any](capacity int, delta float64) *HashTable[K, V] {
-	ht := &HashTable[K, V]{
-		capacity: capacity,
-		delta:    delta,
-		items:    0,
-		c:        4,
+// NewHashTableWithHasher is NewHashTable with a caller-supplied hash
+// function, which lets any comparable type be used as a key. A nil hasher
+// falls back to HashKey and panics if K is not a type HashKey supports.
+func NewHashTableWithHasher[K comparable, V any](capacity int, delta float64, hasher func(K) uint64, opts ...Option) *HashTable[K, V] {
+	if hasher == nil && !hashable[K]() {
+		panic(fmt.Sprintf("elastichash: no default hasher for key type %T", *new(K)))
+	}
+	return newHashTable[K, V](capacity, delta, hashSeed, hasher, opts)
+}
+
+// NewHashableHashTable is NewHashTable for keys that implement Hashable;
+// probing uses each key's own HashCode.
+func NewHashableHashTable[K interface {
+	comparable
+	Hashable
+}, V any](capacity int, delta float64, opts ...Option) *HashTable[K, V] {
+	return newHashTable[K, V](capacity, delta, hashSeed, K.HashCode, opts)
+}
+
+// NewHashTableWithOptions is NewHashTable configured by an Options struct,
+// with opts applied after the struct's fields for settings it does not
+// cover. It panics on invalid settings, including a nil Hasher for a key
+// type HashKey does not support.
+func NewHashTableWithOptions[K comparable, V any](capacity int, o Options[K], opts ...Option) *HashTable[K, V] {
+	if o.Hasher == nil && !hashable[K]() {
+		panic(fmt.Sprintf("elastichash: no default hasher for key type %T", *new(K)))
+	}
+	delta := o.Delta
+	if delta == 0 {
+		delta = defaultDelta
+	}
+	seed := o.Seed
+	if seed == (maphash.Seed{}) {
+		seed = hashSeed
+	}
+	return newHashTable[K, V](capacity, delta, seed, o.Hasher, append(o.options(), opts...))
+}
+
+func newHashTable[K comparable, V any](capacity int, delta float64, seed maphash.Seed, hasher func(K) uint64, opts []Option) *HashTable[K, V] {
+	ht, err := tryNewHashTable[K, V](capacity, delta, seed, hasher, opts)
+	if err != nil {
+		panic(err)
 	}
-	ht.clear()
 	return ht
 }
 
+func tryNewHashTable[K comparable, V any](capacity int, delta float64, seed maphash.Seed, hasher func(K) uint64, opts []Option) (*HashTable[K, V], error) {
+	if err := validateArgs(capacity, delta); err != nil {
+		return nil, err
+	}
+	ht := &HashTable[K, V]{tableState: tableState[K, V]{
+		capacity:  capacity,
+		delta:     delta,
+		seed:      seed,
+		hasher:    hasher,
+		c:         4,
+		threshold: defaultThreshold,
+	}}
+	for _, opt := range opts {
+		opt(&ht.opts)
+	}
+	if ht.opts.err != nil {
+		return nil, ht.opts.err
+	}
+	if ht.opts.c > 0 {
+		ht.c = ht.opts.c
+	}
+	if ht.opts.threshold > 0 {
+		ht.threshold = ht.opts.threshold
+	}
+	if ht.opts.normalizer != nil {
+		normalize, ok := ht.opts.normalizer.(func(K) K)
+		if !ok {
+			return nil, fmt.Errorf("elastichash: key normalizer is a %T, not a func(%T) %T", ht.opts.normalizer, *new(K), *new(K))
+		}
+		ht.normalize = normalize
+	}
+	ht.clear()
+	return ht, nil
+}
+
+// validateArgs rejects a capacity or delta that would leave maxLen without a
+// usable value.
+func validateArgs(capacity int, delta float64) error {
+	if capacity < 1 {
+		return fmt.Errorf("elastichash: capacity must be at least 1, got %d", capacity)
+	}
+	if !(delta > 0 && delta < 1) {
+		return fmt.Errorf("elastichash: delta must be in (0, 1), got %v", delta)
+	}
+	return nil
+}
+
+// emptyCopy returns an empty table with ht's configuration and the given
+// capacity.
+func (ht *HashTable[K, V]) emptyCopy(capacity int) *HashTable[K, V] {
+	next := &HashTable[K, V]{tableState: tableState[K, V]{
+		capacity:  capacity,
+		delta:     ht.delta,
+		seed:      ht.seed,
+		hasher:    ht.hasher,
+		normalize: ht.normalize,
+		c:         ht.c,
+		threshold: ht.threshold,
+		funnel:    ht.funnel,
+		opts:      ht.opts,
+	}}
+	next.clear()
+	return next
+}
+
+// clear allocates empty levels for ht.capacity. Every level but the last
+// takes a geometric share of what is left, at least one slot, and the last
+// level takes the remainder, so the level sizes always sum to the capacity.
 func (ht *HashTable[K, V]) clear() {
 	numLevels := math.Max(1, math.Floor(math.Log2(float64(ht.capacity))))
 	remaining := float64(ht.capacity)
@@ -71,32 +344,395 @@ func (ht *HashTable[K, V]) clear() {
 		sizes = append(sizes, int(size))
 		remaining -= size
 	}
-	ht.levels = make([][]*entry[K, V], int(numLevels))
+	sizes = append(sizes, int(remaining))
+	ht.levels = make([][]slot[K, V], int(numLevels))
 	ht.occupanciesByLevel = make([]int, int(numLevels))
+	ht.tombstonesByLevel = make([]int, int(numLevels))
+	ht.depthsByLevel = make([]int64, int(numLevels))
+	ht.limitsByLevel = make([]int64, int(numLevels))
 	for i, s := range sizes {
-		ht.levels[i] = make([]*entry[K, V], s)
+		ht.levels[i] = make([]slot[K, V], s)
 		ht.occupanciesByLevel[i] = 0
+		ht.limitsByLevel[i] = ht.computeProbeLimit(i)
+	}
+	ht.spillDepth = 0
+	ht.sharedLevels = nil
+	ht.sharedBloom = false
+	ht.bloom = nil
+	if ht.opts.bloom {
+		ht.bloom = newBloomFilter(ht.capacity)
+	}
+}
+
+// Reset removes every entry, tombstones included, while keeping the table's
+// capacity, delta and options.
+func (ht *HashTable[K, V]) Reset() {
+	ht.checkWritable()
+	ht.items.Store(0)
+	ht.clear()
+}
+
+// Freeze makes ht read-only. Afterwards every method that would modify it
+// fails: those that return an error return FrozenErr, and the rest, such as
+// Delete and Update, panic with FrozenErr. Reads keep working, and Clone
+// returns a copy that is not frozen.
+func (ht *HashTable[K, V]) Freeze() {
+	ht.frozen = true
+}
+
+// Frozen reports whether Freeze has been called on ht.
+func (ht *HashTable[K, V]) Frozen() bool {
+	return ht.frozen
+}
+
+// checkWritable panics with FrozenErr if ht is frozen.
+func (ht *HashTable[K, V]) checkWritable() {
+	if ht.frozen {
+		panic(FrozenErr)
 	}
 }
 
+// Len returns the number of live entries in the table.
+func (ht *HashTable[K, V]) Len() int {
+	return int(ht.items.Load())
+}
+
+// Cap returns the capacity the table was configured with.
+func (ht *HashTable[K, V]) Cap() int {
+	return ht.capacity
+}
+
+// Delta returns the fraction of slots the table keeps free.
+func (ht *HashTable[K, V]) Delta() float64 {
+	return ht.delta
+}
+
+// C returns the constant scaling each level's probe limit; see WithC.
+func (ht *HashTable[K, V]) C() float64 {
+	return ht.c
+}
+
+// Threshold returns the next-level free fraction below which Insert commits
+// to the current level; see WithThreshold.
+func (ht *HashTable[K, V]) Threshold() float64 {
+	return ht.threshold
+}
+
 func (ht *HashTable[K, V]) maxLen() int {
-	return ht.capacity - int(ht.delta*float64(ht.capacity))
+	return ht.maxLenAt(ht.capacity)
+}
+
+// maxLenAt is maxLen for a table of the given capacity and ht's delta.
+func (ht *HashTable[K, V]) maxLenAt(capacity int) int {
+	return capacity - int(ht.delta*float64(capacity))
+}
+
+// normalized applies the WithKeyNormalizer function, if any, to key. Keys
+// are normalized once on the way in, so stored keys are already normal.
+func (ht *HashTable[K, V]) normalized(key K) K {
+	if ht.normalize != nil {
+		return ht.normalize(key)
+	}
+	return key
+}
+
+// hash returns the hash of key under the table's hasher, or under HashKey's
+// scheme with the table's seed when no hasher was supplied.
+func (ht *HashTable[K, V]) hash(key K) uint64 {
+	if ht.hasher != nil {
+		return ht.hasher(key)
+	}
+	return hashKey(ht.seed, key)
+}
+
+// probe returns the slot for the j-th probe of hash h on a level of the given
+// size, using the table's ProbeFunc.
+func (ht *HashTable[K, V]) probe(h uint64, j int64, size int) int {
+	if ht.opts.probe != nil {
+		return ht.opts.probe(h, j, size)
+	}
+	return QuadraticProbe(h, j, size)
+}
+
+// probeLimit returns how many probes an operation may spend on level i.
+// Tombstoned slots count as used so the limit never shrinks after a Delete,
+// which keeps entries placed deep in a probe sequence reachable.
+func (ht *HashTable[K, V]) probeLimit(i int) int64 {
+	// The limit only moves when a slot goes from empty to used, so claim
+	// refreshes it then and reads never write, keeping Get safe to call
+	// from several goroutines at once.
+	return ht.limitsByLevel[i]
 }
 
-func (ht *HashTable[K, V]) probe(key K, j int64, size int) int {
-	masked := HashKey(key) & 0xFFFFFFFF
-	return int(int64(masked)+j*j) % size
+// computeProbeLimit evaluates c·min(log2(1/ε), log2(1/δ)) for the level's
+// free fraction ε. A full level makes 1/ε infinite, leaving the δ bound in
+// charge, and the result is clamped to at least one probe so that no level
+// with room is skipped outright.
+func (ht *HashTable[K, V]) computeProbeLimit(i int) int64 {
+	size := len(ht.levels[i])
+	if size == 0 {
+		return 1
+	}
+	freeOnLevel := size - ht.occupanciesByLevel[i] - ht.tombstonesByLevel[i]
+	load := float64(max(freeOnLevel, 0)) / float64(size)
+	limit := ht.c * math.Min(math.Log2(1/load), math.Log2(1/ht.delta))
+	if !(limit >= 1) {
+		return 1
+	}
+	return int64(limit)
 }
 
-func (ht *HashTable[K, V]) Insert(key K, value V) error {
-	if ht.items >= ht.maxLen() {
-		return OutOfSpaceErr
+// place stores key in the first empty or tombstoned slot within probeLimit
+// probes on level i. It reports whether it found one and how many slots it
+// inspected. depthsByLevel remembers the deepest probe that placed a key so
+// lookups can retrace placements made past the usual limit.
+func (ht *HashTable[K, V]) place(i int, h uint64, key K, value V, probeLimit int64) (bool, int) {
+	size := len(ht.levels[i])
+	for j := range probeLimit {
+		if ht.claim(i, ht.probe(h, j, size), h, key, value) {
+			ht.depthsByLevel[i] = max(ht.depthsByLevel[i], j+1)
+			return true, int(j) + 1
+		}
+	}
+	return false, int(max(probeLimit, 0))
+}
+
+// spill is the last resort on the final level once its probe budget is
+// spent: it sweeps the level linearly from the key's home slot, so an insert
+// only fails there when the level is completely full. spillDepth records how
+// far any sweep has gone so that find knows how much of it to retrace, which
+// every miss pays in full; GetWithProbes documents what that costs.
+func (ht *HashTable[K, V]) spill(h uint64, key K, value V) (bool, int) {
+	i := len(ht.levels) - 1
+	size := len(ht.levels[i])
+	if size == 0 {
+		return false, 0
+	}
+	home := ht.probe(h, 0, size)
+	for j := range size {
+		if ht.claim(i, (home+j)%size, h, key, value) {
+			ht.spillDepth = max(ht.spillDepth, j+1)
+			return true, j + 1
+		}
+	}
+	return false, size
+}
+
+// claim stores key in slot idx of level i if that slot is empty or
+// tombstoned and reports whether it did.
+func (ht *HashTable[K, V]) claim(i, idx int, h uint64, key K, value V) bool {
+	state := ht.levels[i][idx].state
+	if state == occupied {
+		return false
+	}
+	wasTombstone := state == tombstone
+	*ht.writable(i, idx) = slot[K, V]{key: key, value: value, hash: h, state: occupied}
+	ht.occupanciesByLevel[i] += 1
+	ht.items.Add(1)
+	if wasTombstone {
+		ht.tombstonesByLevel[i] -= 1
+	} else {
+		ht.limitsByLevel[i] = ht.computeProbeLimit(i)
+	}
+	if ht.bloom != nil {
+		ht.ownBloom()
+		ht.bloom.add(h)
+	}
+	return true
+}
+
+// Insert stores value under key. If key is already present its value is
+// overwritten in place and replaced is true; otherwise a new slot is claimed.
+// Tables built WithAutoGrow double their capacity rather than fail; other
+// tables return an *InsertError wrapping OutOfSpaceErr or FailedToInsertErr.
+func (ht *HashTable[K, V]) Insert(key K, value V) (replaced bool, err error) {
+	replaced, _, err = ht.InsertWithProbes(key, value)
+	return replaced, err
+}
+
+// InsertWithProbes is Insert that also reports how many slots were inspected,
+// counting both the search for an existing entry and the search for a free
+// slot.
+func (ht *HashTable[K, V]) InsertWithProbes(key K, value V) (replaced bool, probes int, err error) {
+	if ht.frozen {
+		return false, 0, FrozenErr
+	}
+	replaced, level, probes, err := ht.insert(key, value)
+	for ht.opts.autoGrow && (errors.Is(err, OutOfSpaceErr) || errors.Is(err, FailedToInsertErr)) {
+		ht.grow()
+		var more int
+		replaced, level, more, err = ht.insert(key, value)
+		probes += more
+	}
+	ht.maxProbes = max(ht.maxProbes, probes)
+	if ht.opts.observer != nil && err == nil {
+		ht.opts.observer.OnInsert(probes, level)
+	}
+	return replaced, probes, err
+}
+
+// grow doubles the capacity until every live entry rehashes successfully.
+func (ht *HashTable[K, V]) grow() {
+	capacity := ht.capacity
+	for {
+		capacity = max(1, 2*capacity)
+		if ht.rehash(capacity) == nil {
+			return
+		}
+	}
+}
+
+// InsertMany inserts entries in order, stopping at the first one Insert
+// rejects. It returns how many entries were stored, replacements included,
+// along with the error for the entry that failed.
+func (ht *HashTable[K, V]) InsertMany(entries []Entry[K, V]) (inserted int, err error) {
+	for _, p := range entries {
+		if _, err := ht.Insert(p.Key, p.Value); err != nil {
+			return inserted, err
+		}
+		inserted++
+	}
+	return inserted, nil
+}
+
+// Resize rebuilds the table at newCapacity, growing or shrinking it, and
+// reinserts every live entry. It fails with an error wrapping OutOfSpaceErr if
+// the entries would not fit under the new MaxLen; on any error ht is left as
+// it was.
+func (ht *HashTable[K, V]) Resize(newCapacity int) error {
+	if ht.frozen {
+		return FrozenErr
+	}
+	if err := validateArgs(newCapacity, ht.delta); err != nil {
+		return err
+	}
+	if ht.Len() > ht.maxLenAt(newCapacity) {
+		return fmt.Errorf("elastichash: %d items do not fit in capacity %d: %w", ht.Len(), newCapacity, OutOfSpaceErr)
+	}
+	return ht.rehash(newCapacity)
+}
+
+// Reserve grows the table, doubling its capacity as grow does, until at least
+// n entries fit under MaxLen, so that n inserts need no further resizing. It
+// does nothing if the table already has room for n, and fails with an error
+// wrapping OutOfSpaceErr, leaving ht as it was, if doubling would overflow an
+// int first.
+func (ht *HashTable[K, V]) Reserve(n int) error {
+	if ht.frozen {
+		return FrozenErr
+	}
+	if n <= ht.maxLen() {
+		return nil
+	}
+	tooLarge := fmt.Errorf("elastichash: no capacity holds %d items: %w", n, OutOfSpaceErr)
+	capacity := ht.capacity
+	for ht.maxLenAt(capacity) < n {
+		if capacity > math.MaxInt/2 {
+			return tooLarge
+		}
+		capacity *= 2
+	}
+	for ht.rehash(capacity) != nil {
+		if capacity > math.MaxInt/2 {
+			return tooLarge
+		}
+		capacity *= 2
+	}
+	return nil
+}
+
+// ShrinkToFit rebuilds the table at the smallest capacity whose MaxLen holds
+// its live entries, releasing level arrays left oversized by deletes. If the
+// tight fit cannot place every entry it retries at doubled capacities, and it
+// does nothing when no smaller capacity works.
+func (ht *HashTable[K, V]) ShrinkToFit() {
+	ht.checkWritable()
+	for capacity := fitCapacity(ht.Len(), ht.delta); capacity < ht.capacity; capacity *= 2 {
+		if ht.rehash(capacity) == nil {
+			return
+		}
+	}
+}
+
+// rehash rebuilds the table at the given capacity and counts the resize.
+func (ht *HashTable[K, V]) rehash(capacity int) error {
+	if err := ht.rebuild(capacity); err != nil {
+		return err
+	}
+	ht.resizes++
+	return nil
+}
+
+// compactAt is the share of a table's slots that may be tombstones before
+// Delete rebuilds it.
+const compactAt = 0.25
+
+// Compact rebuilds the table at its current capacity, dropping tombstones
+// and resetting the probe depths that lookups retrace, which shortens probes
+// after a run of deletes. If the rebuild fails the table is left as it was.
+//
+// Clearing a slot in place cannot work here: a key's position depends on the
+// level choices made while the table filled up, so there is no probe chain
+// to shift back along. Delete calls Compact itself once tombstones pass
+// compactAt of the capacity, which keeps probe lengths bounded under churn
+// at an amortized O(1) per delete.
+func (ht *HashTable[K, V]) Compact() {
+	ht.checkWritable()
+	_ = ht.rebuild(ht.capacity)
+}
+
+// rebuild reinserts every live entry into a fresh table of the given
+// capacity, so on failure ht is untouched.
+func (ht *HashTable[K, V]) rebuild(capacity int) error {
+	next := ht.emptyCopy(capacity)
+	for _, level := range ht.levels {
+		for _, e := range level {
+			if e.state != occupied {
+				continue
+			}
+			if _, _, _, err := next.insertHash(e.hash, e.key, e.value); err != nil {
+				return err
+			}
+		}
+	}
+	next.resizes = ht.resizes
+	next.maxProbes = ht.maxProbes
+	ht.adopt(next)
+	return nil
+}
+
+// adopt replaces ht's contents with next's, which the caller must not use
+// afterwards.
+func (ht *HashTable[K, V]) adopt(next *HashTable[K, V]) {
+	ht.tableState = next.tableState
+	ht.items.Store(next.items.Load())
+}
+
+func (ht *HashTable[K, V]) insert(key K, value V) (bool, int, int, error) {
+	key = ht.normalized(key)
+	return ht.insertHash(ht.hash(key), key, value)
+}
+
+// insertHash is insert for a key whose hash h is already known. Besides
+// whether key was replaced it returns the level now holding key, or -1 on
+// failure, and the number of slots inspected.
+func (ht *HashTable[K, V]) insertHash(h uint64, key K, value V) (bool, int, int, error) {
+	i, idx, probes, ok := ht.lookup(h, key)
+	if ok {
+		ht.writable(i, idx).value = value
+		return true, i, probes, nil
+	}
+	if ht.Len() >= ht.maxLen() {
+		return false, -1, probes, &InsertError{Err: OutOfSpaceErr, Items: ht.Len(), MaxLen: ht.maxLen(), Level: -1}
+	}
+	if ht.funnel {
+		return ht.funnelInsert(h, key, value, probes)
 	}
 	for i, l := range ht.levels {
 		size := len(l)
 		freeOnLevel := size - ht.occupanciesByLevel[i]
 		load := float64(freeOnLevel) / float64(size)
-		probeLimit := int64(math.Max(1, ht.c*math.Min(math.Log2(math.Max(1/load, 0)), math.Log2(1/ht.delta))))
+		probeLimit := ht.probeLimit(i)
 		if i < len(ht.levels)-1 {
 			nextLevel := ht.levels[i+1]
 			nextOccupancy := ht.occupanciesByLevel[i+1]
@@ -105,77 +741,480 @@ func (ht *HashTable[K, V]) Insert(key K, value V) error {
 			if len(nextLevel) > 0 {
 				nextLoad = nextFreeOnLevel / float64(len(nextLevel))
 			}
-			if load > (ht.delta/2) && nextLoad > threshold {
-				for j := range probeLimit {
-					idx := ht.probe(key, j, size)
-					if l[idx] == nil {
-						l[idx] = &entry[K, V]{key, value}
-						ht.occupanciesByLevel[i] += 1
-						ht.items += 1
-						return nil
-					}
+			if load > (ht.delta/2) && nextLoad > ht.threshold {
+				placed, n := ht.place(i, h, key, value, probeLimit)
+				if probes += n; placed {
+					return false, i, probes, nil
 				}
 			} else if load <= (ht.delta / 2) {
 				continue
-			} else if nextLoad <= threshold {
-				for j := range probeLimit {
-					idx := ht.probe(key, j, size)
-					if l[idx] == nil {
-						l[idx] = &entry[K, V]{key, value}
-						ht.occupanciesByLevel[i] += 1
-						ht.items += 1
-						return nil
-					}
+			} else if nextLoad <= ht.threshold {
+				// The next level is nearly full, so commit to this one and
+				// probe as deep as it takes.
+				placed, n := ht.place(i, h, key, value, int64(size))
+				if probes += n; placed {
+					return false, i, probes, nil
 				}
 			}
 		} else {
-			for j := range probeLimit {
-				idx := ht.probe(key, j, size)
-				if l[idx] == nil {
-					l[idx] = &entry[K, V]{key, value}
-					ht.occupanciesByLevel[i] += 1
-					ht.items += 1
-					return nil
-				}
+			placed, n := ht.place(i, h, key, value, probeLimit)
+			if probes += n; placed {
+				return false, i, probes, nil
+			}
+			placed, n = ht.spill(h, key, value)
+			if probes += n; placed {
+				return false, i, probes, nil
 			}
 		}
 	}
-	return FailedToInsertErr
+	// The pass above gives up on a level once its probe budget runs out, and
+	// skips nearly full levels outright, so before failing retry every
+	// earlier level that has a free slot at full depth.
+	last := len(ht.levels) - 1
+	for i := range last {
+		size := len(ht.levels[i])
+		if ht.occupanciesByLevel[i] == size {
+			continue
+		}
+		placed, n := ht.place(i, h, key, value, int64(size))
+		if probes += n; placed {
+			return false, i, probes, nil
+		}
+	}
+	return false, -1, probes, &InsertError{
+		Err:        FailedToInsertErr,
+		Items:      ht.Len(),
+		MaxLen:     ht.maxLen(),
+		Level:      last,
+		Occupancy:  ht.occupanciesByLevel[last],
+		ProbeLimit: ht.probeLimit(last),
+	}
 }
 
 func (ht *HashTable[K, V]) Get(key K) (V, bool) {
+	v, ok, _ := ht.GetWithProbes(key)
+	return v, ok
+}
+
+// MustGet is Get for keys the caller knows are present; it panics if key is
+// missing.
+func (ht *HashTable[K, V]) MustGet(key K) V {
+	v, ok := ht.Get(key)
+	if !ok {
+		panic(fmt.Sprintf("elastichash: key %v not found", key))
+	}
+	return v
+}
+
+// GetWithDefault returns the value stored under key, or def if key is
+// missing.
+func (ht *HashTable[K, V]) GetWithDefault(key K, def V) V {
+	if v, ok := ht.Get(key); ok {
+		return v
+	}
+	return def
+}
+
+// GetMany looks up every key, returning values and found flags aligned with
+// keys; a missing key gets V's zero value and false.
+func (ht *HashTable[K, V]) GetMany(keys []K) ([]V, []bool) {
+	values := make([]V, len(keys))
+	found := make([]bool, len(keys))
+	for i, key := range keys {
+		values[i], found[i] = ht.Get(key)
+	}
+	return values, found
+}
+
+// GetWithProbes is Get that also reports how many slots were inspected; a key
+// found at the first slot of its probe sequence reports 1.
+//
+// A miss inspects every slot that could hold key: on each level up to the
+// deepest probe any insert has placed a key at, and on the last level the
+// longest linear sweep any insert has needed. Those depths only grow until
+// the table is reset or rebuilt, so misses on a table filled close to MaxLen
+// are expensive: at capacity 1<<20 a miss inspects thousands of slots with
+// delta 0.02 and several hundred with delta 0.1. Leaving headroom, through a
+// larger delta or by growing early, keeps misses cheap.
+func (ht *HashTable[K, V]) GetWithProbes(key K) (V, bool, int) {
 	toReturn := new(V)
+	key = ht.normalized(key)
+	i, idx, probes, ok := ht.lookup(ht.hash(key), key)
+	if ht.opts.observer != nil {
+		ht.opts.observer.OnGet(ok, probes)
+	}
+	if !ok {
+		return *toReturn, false, probes
+	}
+	return ht.levels[i][idx].value, true, probes
+}
+
+// Contains reports whether key is present without copying out its value.
+func (ht *HashTable[K, V]) Contains(key K) bool {
+	_, _, ok := ht.find(key)
+	return ok
+}
+
+// find walks the probe sequence for key across every level and returns the
+// level and slot index holding it.
+func (ht *HashTable[K, V]) find(key K) (int, int, bool) {
+	key = ht.normalized(key)
+	i, idx, _, ok := ht.lookup(ht.hash(key), key)
+	return i, idx, ok
+}
+
+// lookup is find for a key whose hash h has already been computed. It also
+// returns the number of slots inspected, which is zero when the Bloom filter
+// rules the key out.
+func (ht *HashTable[K, V]) lookup(h uint64, key K) (int, int, int, bool) {
+	if ht.bloom != nil && !ht.bloom.mayContain(h) {
+		return 0, 0, 0, false
+	}
+	if ht.funnel {
+		return ht.funnelLookup(h, key)
+	}
+	probes := 0
 	for i, level := range ht.levels {
 		size := len(level)
-		freeOnLevel := size - ht.occupanciesByLevel[i]
-		load := float64(freeOnLevel) / float64(size)
-		probeLimit := int64(math.Max(1, ht.c*math.Min(math.Log2(math.Max(1/load, 0)), math.Log2(1/ht.delta))))
+		probeLimit := max(ht.probeLimit(i), ht.depthsByLevel[i])
 		for j := range probeLimit {
-			idx := ht.probe(key, int64(j), size)
-			if level[idx] == nil {
-				continue
-			} else if level[idx].key == key {
-				return level[idx].value, true
+			idx := ht.probe(h, j, size)
+			probes++
+			if e := &level[idx]; e.state == occupied && e.hash == h && e.key == key {
+				return i, idx, probes, true
 			}
 		}
 	}
-	return *toReturn, false
+	return ht.lookupSpill(h, key, probes)
 }
 
+// lookupSpill retraces the last level's linear sweeps after probes slots
+// have already been checked elsewhere.
+func (ht *HashTable[K, V]) lookupSpill(h uint64, key K, probes int) (int, int, int, bool) {
+	last := len(ht.levels) - 1
+	level := ht.levels[last]
+	if ht.spillDepth > 0 {
+		home := ht.probe(h, 0, len(level))
+		for j := range ht.spillDepth {
+			idx := (home + j) % len(level)
+			probes++
+			if e := &level[idx]; e.state == occupied && e.hash == h && e.key == key {
+				return last, idx, probes, true
+			}
+		}
+	}
+	return 0, 0, probes, false
+}
+
+// GetOrInsert returns the value stored under key with loaded set to true, or
+// inserts value and returns it with loaded false. The error is Insert's, for
+// a table with no room for the new key.
+func (ht *HashTable[K, V]) GetOrInsert(key K, value V) (actual V, loaded bool, err error) {
+	if i, idx, ok := ht.find(key); ok {
+		return ht.levels[i][idx].value, true, nil
+	}
+	if _, err := ht.Insert(key, value); err != nil {
+		return *new(V), false, err
+	}
+	return value, false, nil
+}
+
+// Swap stores value under key and returns the value it replaced, with loaded
+// reporting whether there was one, as sync.Map.Swap does. The error is
+// Insert's, for a new key the table has no room for; an existing key is
+// always swapped.
+func (ht *HashTable[K, V]) Swap(key K, value V) (previous V, loaded bool, err error) {
+	if ht.frozen {
+		return *new(V), false, FrozenErr
+	}
+	if i, idx, ok := ht.find(key); ok {
+		e := ht.writable(i, idx)
+		previous, e.value = e.value, value
+		return previous, true, nil
+	}
+	_, err = ht.Insert(key, value)
+	return *new(V), false, err
+}
+
+// ComputeIfAbsent returns the value stored under key, calling f to build and
+// insert one only when key is missing. Insert's error is returned if the new
+// value does not fit.
+func (ht *HashTable[K, V]) ComputeIfAbsent(key K, f func(K) V) (V, error) {
+	if i, idx, ok := ht.find(key); ok {
+		return ht.levels[i][idx].value, nil
+	}
+	value := f(key)
+	if _, err := ht.Insert(key, value); err != nil {
+		return *new(V), err
+	}
+	return value, nil
+}
+
+// Update overwrites the value stored under key and reports whether key was
+// present. Unlike Insert it never claims a new slot.
+func (ht *HashTable[K, V]) Update(key K, value V) bool {
+	ht.checkWritable()
+	i, idx, ok := ht.find(key)
+	if !ok {
+		return false
+	}
+	ht.writable(i, idx).value = value
+	return true
+}
+
+// Apply replaces the value of every live entry with f(key, value) in place.
+// Entries keep their slots, so nothing is rehashed. f must not modify the
+// table.
+func (ht *HashTable[K, V]) Apply(f func(K, V) V) {
+	ht.checkWritable()
+	for i, level := range ht.levels {
+		for j := range level {
+			if level[j].state == occupied {
+				e := ht.writable(i, j)
+				e.value = f(e.key, e.value)
+			}
+		}
+	}
+}
+
+// Delete removes key from the table and reports whether it was present.
+// Clearing the slot outright would break the probe sequence of any key that
+// was pushed past it, so the slot is left as a tombstone that lookups skip
+// over and that Insert reuses. Once tombstones make up a quarter of the
+// slots the table is compacted.
+func (ht *HashTable[K, V]) Delete(key K) bool {
+	ht.checkWritable()
+	i, idx, ok := ht.find(key)
+	if !ok {
+		return false
+	}
+	ht.remove(i, idx)
+	return true
+}
+
+// DeleteMany deletes every key in keys and returns how many were present.
+func (ht *HashTable[K, V]) DeleteMany(keys []K) int {
+	deleted := 0
+	for _, key := range keys {
+		if ht.Delete(key) {
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// Pop removes key and returns the value it held, or the zero value and false
+// if key was not present.
+func (ht *HashTable[K, V]) Pop(key K) (V, bool) {
+	ht.checkWritable()
+	i, idx, ok := ht.find(key)
+	if !ok {
+		return *new(V), false
+	}
+	value := ht.levels[i][idx].value
+	ht.remove(i, idx)
+	return value, true
+}
+
+// CompareAndSwap stores new under key only if its current value is == old,
+// and reports whether it did. A missing key is never inserted. It panics if
+// V is not comparable.
+func (ht *HashTable[K, V]) CompareAndSwap(key K, old, new V) bool {
+	ht.checkWritable()
+	eq := comparableValues[V]("CompareAndSwap")
+	i, idx, ok := ht.find(key)
+	if !ok || !eq(ht.levels[i][idx].value, old) {
+		return false
+	}
+	ht.writable(i, idx).value = new
+	return true
+}
+
+// CompareAndDelete removes key only if its value is == old, and reports
+// whether it did. It panics if V is not comparable.
+func (ht *HashTable[K, V]) CompareAndDelete(key K, old V) bool {
+	ht.checkWritable()
+	eq := comparableValues[V]("CompareAndDelete")
+	i, idx, ok := ht.find(key)
+	if !ok || !eq(ht.levels[i][idx].value, old) {
+		return false
+	}
+	ht.remove(i, idx)
+	return true
+}
+
+// remove turns slot idx of level i into a tombstone, compacting the table
+// once tombstones pile up.
+func (ht *HashTable[K, V]) remove(i, idx int) {
+	*ht.writable(i, idx) = slot[K, V]{state: tombstone}
+	ht.occupanciesByLevel[i] -= 1
+	ht.tombstonesByLevel[i] += 1
+	ht.items.Add(-1)
+	if float64(ht.tombstones()) > compactAt*float64(ht.capacity) {
+		ht.Compact()
+	}
+}
+
+// tombstones returns the number of tombstoned slots across all levels.
+func (ht *HashTable[K, V]) tombstones() int {
+	n := 0
+	for _, t := range ht.tombstonesByLevel {
+		n += t
+	}
+	return n
+}
+
+// comparableValues returns == for V, panicking on behalf of the named method
+// if V is not comparable.
+func comparableValues[V any](method string) func(a, b V) bool {
+	if !reflect.TypeFor[V]().Comparable() {
+		panic(fmt.Sprintf("elastichash: %s needs a comparator for value type %v", method, reflect.TypeFor[V]()))
+	}
+	return func(a, b V) bool { return any(a) == any(b) }
+}
+
+// Clone returns an independent copy of ht with the same configuration and
+// layout. Entries are copied rather than shared, so later changes to either
+// table are invisible to the other. Values themselves are copied shallowly.
+// The copy of a frozen table is not frozen.
+func (ht *HashTable[K, V]) Clone() *HashTable[K, V] {
+	c := &HashTable[K, V]{tableState: ht.tableState}
+	c.items.Store(ht.items.Load())
+	c.levels = make([][]slot[K, V], len(ht.levels))
+	for i, level := range ht.levels {
+		c.levels[i] = append([]slot[K, V](nil), level...)
+	}
+	c.occupanciesByLevel = append([]int(nil), ht.occupanciesByLevel...)
+	c.tombstonesByLevel = append([]int(nil), ht.tombstonesByLevel...)
+	c.depthsByLevel = append([]int64(nil), ht.depthsByLevel...)
+	c.limitsByLevel = append([]int64(nil), ht.limitsByLevel...)
+	c.bloom = ht.bloom.clone()
+	c.frozen = false
+	c.sharedLevels = nil
+	c.sharedBloom = false
+	return c
+}
+
+// Merge inserts every live entry of other into ht, with other's value winning
+// when both hold a key. It stops at the first error, typically one wrapping
+// OutOfSpaceErr, and leaves the entries merged so far in place; Clone first
+// if the merge must be all or nothing.
+func (ht *HashTable[K, V]) Merge(other *HashTable[K, V]) error {
+	var err error
+	other.Range(func(key K, value V) bool {
+		_, err = ht.Insert(key, value)
+		return err == nil
+	})
+	return err
+}
+
+// Equal reports whether ht and other hold the same keys mapped to equal
+// values, regardless of capacity or layout. A nil eq compares values with ==
+// and panics if V is not comparable.
+func (ht *HashTable[K, V]) Equal(other *HashTable[K, V], eq func(a, b V) bool) bool {
+	if eq == nil {
+		eq = comparableValues[V]("Equal")
+	}
+	if ht.Len() != other.Len() {
+		return false
+	}
+	equal := true
+	ht.Range(func(key K, value V) bool {
+		v, ok := other.Get(key)
+		equal = ok && eq(value, v)
+		return equal
+	})
+	return equal
+}
+
+// Range calls f for every live entry in unspecified order, stopping early if
+// f returns false. Modifying the table from within f is not supported.
+func (ht *HashTable[K, V]) Range(f func(key K, value V) bool) {
+	for _, level := range ht.levels {
+		for _, e := range level {
+			if e.state == occupied {
+				if !f(e.key, e.value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Count returns how many live entries satisfy pred, or Len when pred is nil.
+func (ht *HashTable[K, V]) Count(pred func(K, V) bool) int {
+	if pred == nil {
+		return ht.Len()
+	}
+	n := 0
+	ht.Range(func(key K, value V) bool {
+		if pred(key, value) {
+			n++
+		}
+		return true
+	})
+	return n
+}
+
+// All returns an iterator over every live entry for use with range-over-func.
+// The slice-returning Keys and Values keep their names; All covers the
+// streaming case.
+func (ht *HashTable[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ht.Range(yield)
+	}
+}
+
+// Entries returns every live entry in unspecified order.
+func (ht *HashTable[K, V]) Entries() []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, ht.Len())
+	ht.Range(func(key K, value V) bool {
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+		return true
+	})
+	return entries
+}
+
+// Keys returns every live key in unspecified order.
+func (ht *HashTable[K, V]) Keys() []K {
+	keys := make([]K, 0, ht.Len())
+	ht.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns every live value in unspecified order.
+func (ht *HashTable[K, V]) Values() []V {
+	values := make([]V, 0, ht.Len())
+	ht.Range(func(_ K, value V) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// String renders the live entries as a JSON object. Keys are formatted with
+// %v and quoted; values are JSON-encoded, so numbers stay unquoted.
 func (ht *HashTable[K, V]) String() string {
 	var sb strings.Builder
 	sb.WriteString("{")
+	first := true
 	for _, level := range ht.levels {
 		for _, e := range level {
-			if e != nil {
-				sb.WriteString("\"")
-				sb.WriteString(fmt.Sprintf("%v", e.key))
-				sb.WriteString("\"")
+			if e.state == occupied {
+				if !first {
+					sb.WriteString(", ")
+				}
+				first = false
+				sb.Write(jsonString(fmt.Sprintf("%v", e.key)))
 				sb.WriteString(": ")
-				sb.WriteString("\"")
-				sb.WriteString(fmt.Sprintf("%v", e.value))
-				sb.WriteString("\"")
-				sb.WriteString(", ")
+				if b, err := json.Marshal(e.value); err == nil {
+					sb.Write(b)
+				} else {
+					sb.Write(jsonString(fmt.Sprintf("%v", e.value)))
+				}
 			}
 		}
 	}
@@ -183,3 +1222,9 @@ func (ht *HashTable[K, V]) String() string {
 	sb.WriteString("}")
 	return sb.String()
 }
+
+func jsonString(s string) []byte {
+	// Marshalling a string cannot fail.
+	b, _ := json.Marshal(s)
+	return b
+}
@@ -1,7 +1,11 @@
 package elastichash_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"hash/maphash"
+	"math"
+	"math/rand/v2"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,6 +19,39 @@ func TestNewHashTable(t *testing.T) {
 	assert.NotNil(t, ht)
 }
 
+func TestTryNewHashTable(t *testing.T) {
+	ht, err := elastichash.TryNewHashTable[string, int](100, 0.1)
+	require.NoError(t, err)
+	assert.Equal(t, 100, ht.Cap())
+
+	tests := []struct {
+		name     string
+		capacity int
+		delta    float64
+		opts     []elastichash.Option
+		msg      string
+	}{
+		{name: "zero capacity", capacity: 0, delta: 0.1, msg: "capacity must be at least 1, got 0"},
+		{name: "negative capacity", capacity: -5, delta: 0.1, msg: "capacity must be at least 1, got -5"},
+		{name: "zero delta", capacity: 10, delta: 0, msg: "delta must be in (0, 1), got 0"},
+		{name: "delta of one", capacity: 10, delta: 1, msg: "delta must be in (0, 1), got 1"},
+		{name: "negative delta", capacity: 10, delta: -0.1, msg: "delta must be in (0, 1), got -0.1"},
+		{name: "NaN delta", capacity: 10, delta: math.NaN(), msg: "delta must be in (0, 1), got NaN"},
+		{name: "invalid option", capacity: 10, delta: 0.1, opts: []elastichash.Option{elastichash.WithC(-1)}, msg: "c must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ht, err := elastichash.TryNewHashTable[string, int](tt.capacity, tt.delta, tt.opts...)
+			require.Error(t, err)
+			assert.Nil(t, ht)
+			assert.Contains(t, err.Error(), tt.msg)
+			assert.Panics(t, func() {
+				elastichash.NewHashTable[string, int](tt.capacity, tt.delta, tt.opts...)
+			})
+		})
+	}
+}
+
 func TestHashTable(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -50,7 +87,7 @@ func TestHashTable(t *testing.T) {
 				{opType: "insert", key: "key3", value: 3},
 				{opType: "insert", key: "key4", value: 4},
 			},
-			expectedError: elastichash.FailedToInsertErr,
+			expectedError: elastichash.OutOfSpaceErr,
 			expectedGet: map[string]int{
 				"key1": 1,
 				"key2": 2,
@@ -94,13 +131,13 @@ func TestHashTable(t *testing.T) {
 			for _, op := range tt.operations {
 				switch op.opType {
 				case "insert":
-					err = ht.Insert(op.key, op.value)
+					_, err = ht.Insert(op.key, op.value)
 				}
 				if err != nil {
 					break
 				}
 			}
-			require.Equal(t, tt.expectedError, err)
+			require.ErrorIs(t, err, tt.expectedError)
 
 			for key, expectedValue := range tt.expectedGet {
 				value, found := ht.Get(key)
@@ -119,9 +156,9 @@ func TestBasic(t *testing.T) {
 	capacity := 10
 	delta := 0.1
 	ht := elastichash.NewHashTable[string, int](capacity, delta)
-	err := ht.Insert("example", 1)
+	_, err := ht.Insert("example", 1)
 	assert.NoError(t, err)
-	err = ht.Insert("example2", 1)
+	_, err = ht.Insert("example2", 1)
 	assert.NoError(t, err)
 	v, ok := ht.Get("example")
 	assert.True(t, ok)
@@ -132,8 +169,1193 @@ func TestBasic(t *testing.T) {
 	fmt.Println(ht.String())
 }
 
+func TestGetMany(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	mustInsert(t, ht, "a", 1)
+	mustInsert(t, ht, "c", 3)
+
+	values, found := ht.GetMany([]string{"a", "b", "c", "a", "d"})
+	assert.Equal(t, []int{1, 0, 3, 1, 0}, values)
+	assert.Equal(t, []bool{true, false, true, true, false}, found)
+
+	values, found = ht.GetMany(nil)
+	assert.Empty(t, values)
+	assert.Empty(t, found)
+}
+
+func TestInsertMany(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](100, 0.1)
+	pairs := make([]elastichash.Entry[int, int], 150)
+	for i := range pairs {
+		pairs[i] = elastichash.Entry[int, int]{Key: i, Value: i * i}
+	}
+	inserted, err := ht.InsertMany(pairs)
+	assert.ErrorIs(t, err, elastichash.OutOfSpaceErr)
+	assert.Equal(t, ht.MaxLen(), inserted)
+	assert.Equal(t, inserted, ht.Len())
+	assert.True(t, ht.Contains(inserted-1))
+	assert.False(t, ht.Contains(inserted))
+
+	inserted, err = ht.InsertMany(pairs[:10])
+	require.NoError(t, err, "replacing existing keys needs no room")
+	assert.Equal(t, 10, inserted)
+}
+
+func TestEntries(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.1)
+	for i := range 30 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	entries := ht.Entries()
+	require.Len(t, entries, 30)
+	assert.Contains(t, entries, elastichash.Entry[string, int]{Key: "key7", Value: 7})
+
+	copied := elastichash.NewHashTable[string, int](50, 0.1)
+	inserted, err := copied.InsertMany(entries)
+	require.NoError(t, err)
+	assert.Equal(t, 30, inserted)
+	assert.True(t, ht.Equal(copied, nil))
+}
+
+func TestInsertErrorContext(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](10, 0.1)
+	for i := range ht.MaxLen() {
+		mustInsert(t, ht, i, i)
+	}
+	_, err := ht.Insert(-1, -1)
+	require.ErrorIs(t, err, elastichash.OutOfSpaceErr)
+	var insertErr *elastichash.InsertError
+	require.ErrorAs(t, err, &insertErr)
+	assert.Equal(t, ht.MaxLen(), insertErr.Items)
+	assert.Equal(t, ht.MaxLen(), insertErr.MaxLen)
+	assert.Equal(t, -1, insertErr.Level)
+
+	// Probing a single slot per level leaves most of the early levels
+	// unreachable, so inserts run out of probes while space remains.
+	stuck := elastichash.NewHashTable[int, int](100, 0.1,
+		elastichash.WithProbeFunc(func(uint64, int64, int) int { return 0 }))
+	for i := 0; ; i++ {
+		if _, err = stuck.Insert(i, i); err != nil {
+			break
+		}
+	}
+	require.ErrorIs(t, err, elastichash.FailedToInsertErr)
+	assert.NotErrorIs(t, err, elastichash.OutOfSpaceErr)
+	require.ErrorAs(t, err, &insertErr)
+	levels := stuck.Stats().Levels
+	last := len(levels) - 1
+	assert.Less(t, insertErr.Items, insertErr.MaxLen)
+	assert.Equal(t, last, insertErr.Level)
+	assert.Equal(t, levels[last].Occupancy, insertErr.Occupancy)
+	assert.Equal(t, levels[last].Size, insertErr.Occupancy, "the spill fills the last level before giving up")
+	assert.Positive(t, insertErr.ProbeLimit)
+	assert.Contains(t, err.Error(), fmt.Sprintf("level %d", last))
+}
+
+func TestInsertTriesEveryLevelBeforeFailing(t *testing.T) {
+	// With every key on the same hash, each level's probe budget covers the
+	// same few slots, so once the last level is full the only free slots
+	// left are deep in the earlier levels' probe sequences.
+	ht := elastichash.NewHashTableWithHasher[int, int](200, 0.1, func(int) uint64 { return 0 }, elastichash.WithC(1))
+	inserted := 0
+	for i := 0; ; i++ {
+		if _, err := ht.Insert(i, i); err != nil {
+			require.ErrorIs(t, err, elastichash.FailedToInsertErr)
+			break
+		}
+		inserted++
+	}
+
+	levels := ht.Stats().Levels
+	reachable := 0
+	for i, level := range levels {
+		if i == len(levels)-1 {
+			reachable += level.Size
+			continue
+		}
+		slots := map[int]bool{}
+		for j := range int64(level.Size) {
+			slots[elastichash.QuadraticProbe(0, j, level.Size)] = true
+		}
+		reachable += len(slots)
+	}
+	assert.Equal(t, reachable, inserted, "every slot on a key's probe sequence should be used")
+	for i := range inserted {
+		v, ok := ht.Get(i)
+		require.True(t, ok, "key %d", i)
+		assert.Equal(t, i, v)
+	}
+}
+
+func TestProbeLimitAtExtremes(t *testing.T) {
+	checkLimits := func(ht *elastichash.HashTable[int, int]) {
+		t.Helper()
+		cached, fresh := ht.ProbeLimits()
+		for i := range cached {
+			assert.GreaterOrEqual(t, cached[i], int64(1), "level %d", i)
+			assert.Equal(t, fresh[i], cached[i], "level %d", i)
+		}
+	}
+
+	empty := elastichash.NewHashTable[int, int](100, 0.1)
+	checkLimits(empty)
+	mustInsert(t, empty, 1, 1)
+	assert.Equal(t, 1, empty.MustGet(1))
+
+	// A capacity-1 table has a single one-slot level that fills up exactly.
+	full := elastichash.NewHashTable[int, int](1, 0.1)
+	mustInsert(t, full, 1, 1)
+	require.Equal(t, 1, full.MaxLen())
+	checkLimits(full)
+	_, err := full.Insert(2, 2)
+	assert.ErrorIs(t, err, elastichash.OutOfSpaceErr)
+	require.True(t, full.Delete(1))
+	checkLimits(full)
+	mustInsert(t, full, 2, 2)
+	assert.Equal(t, 2, full.MustGet(2))
+
+	// Filling every slot of the larger table leaves the full levels with a
+	// finite limit from δ alone.
+	packed := elastichash.NewHashTable[int, int](64, 0.01)
+	for i := range packed.MaxLen() {
+		mustInsert(t, packed, i, i)
+	}
+	checkLimits(packed)
+	for i := range packed.MaxLen() {
+		assert.Equal(t, i, packed.MustGet(i))
+	}
+}
+
+func TestTinyCapacities(t *testing.T) {
+	for _, capacity := range []int{1, 2} {
+		t.Run(fmt.Sprintf("capacity %d", capacity), func(t *testing.T) {
+			ht := elastichash.NewHashTable[string, int](capacity, 0.1)
+			require.Equal(t, capacity, ht.MaxLen())
+			for i := range capacity {
+				mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+			}
+			for i := range capacity {
+				v, ok := ht.Get(fmt.Sprintf("key%d", i))
+				require.True(t, ok)
+				assert.Equal(t, i, v)
+			}
+			assert.False(t, ht.Contains("missing"))
+			_, err := ht.Insert("overflow", -1)
+			assert.ErrorIs(t, err, elastichash.OutOfSpaceErr)
+
+			replaced, err := ht.Insert("key0", 10)
+			require.NoError(t, err)
+			assert.True(t, replaced)
+			require.True(t, ht.Delete("key0"))
+			mustInsert(t, ht, "other", 1)
+			assert.Equal(t, 1, ht.MustGet("other"))
+		})
+	}
+}
+
+func TestInsertReplacesInPlace(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	replaced, err := ht.Insert("key1", 1)
+	require.NoError(t, err)
+	assert.False(t, replaced)
+
+	replaced, err = ht.Insert("key1", 2)
+	require.NoError(t, err)
+	assert.True(t, replaced)
+	assert.Equal(t, []int{1}, nonZero(ht.LevelOccupancies()), "the duplicate should not claim a second slot")
+
+	v, ok := ht.Get("key1")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+}
+
+func TestInsertReplacesInFullTable(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](3, 0.1)
+	for i := range ht.MaxLen() {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	replaced, err := ht.Insert("key0", 100)
+	require.NoError(t, err)
+	assert.True(t, replaced)
+	v, _ := ht.Get("key0")
+	assert.Equal(t, 100, v)
+}
+
+func TestContains(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	mustInsert(t, ht, "key1", 1)
+	mustInsert(t, ht, "key2", 2)
+	require.True(t, ht.Delete("key2"))
+
+	assert.True(t, ht.Contains("key1"))
+	assert.False(t, ht.Contains("key2"), "deleted keys should not be reported")
+	assert.False(t, ht.Contains("key3"))
+}
+
+func TestSwap(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	previous, loaded, err := ht.Swap("a", 1)
+	require.NoError(t, err)
+	assert.False(t, loaded)
+	assert.Zero(t, previous)
+	assert.Equal(t, 1, ht.MustGet("a"))
+
+	previous, loaded, err = ht.Swap("a", 2)
+	require.NoError(t, err)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, previous)
+	assert.Equal(t, 2, ht.MustGet("a"))
+	assert.Equal(t, 1, ht.Len())
+
+	for i := ht.Len(); i < ht.MaxLen(); i++ {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	_, loaded, err = ht.Swap("missing", 3)
+	assert.ErrorIs(t, err, elastichash.OutOfSpaceErr)
+	assert.False(t, loaded)
+	previous, loaded, err = ht.Swap("a", 4)
+	require.NoError(t, err, "swapping an existing key needs no room")
+	assert.True(t, loaded)
+	assert.Equal(t, 2, previous)
+}
+
+func TestGetOrInsert(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	v, loaded, err := ht.GetOrInsert("a", 1)
+	require.NoError(t, err)
+	assert.False(t, loaded)
+	assert.Equal(t, 1, v)
+
+	v, loaded, err = ht.GetOrInsert("a", 2)
+	require.NoError(t, err)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, v, "an existing value is kept")
+	assert.Equal(t, 1, ht.Len())
+
+	for i := ht.Len(); i < ht.MaxLen(); i++ {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	v, loaded, err = ht.GetOrInsert("missing", 3)
+	assert.ErrorIs(t, err, elastichash.OutOfSpaceErr)
+	assert.False(t, loaded)
+	assert.Zero(t, v)
+	assert.False(t, ht.Contains("missing"))
+
+	v, loaded, err = ht.GetOrInsert("a", 4)
+	require.NoError(t, err, "hits succeed even when the table is full")
+	assert.True(t, loaded)
+	assert.Equal(t, 1, v)
+}
+
+func TestComputeIfAbsent(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	calls := 0
+	length := func(k string) int {
+		calls++
+		return len(k)
+	}
+	v, err := ht.ComputeIfAbsent("four", length)
+	require.NoError(t, err)
+	assert.Equal(t, 4, v)
+	assert.Equal(t, 1, calls)
+
+	require.True(t, ht.Update("four", 40))
+	v, err = ht.ComputeIfAbsent("four", length)
+	require.NoError(t, err)
+	assert.Equal(t, 40, v)
+	assert.Equal(t, 1, calls, "the factory is not called for a present key")
+
+	for i := ht.Len(); i < ht.MaxLen(); i++ {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	_, err = ht.ComputeIfAbsent("overflow", length)
+	assert.ErrorIs(t, err, elastichash.OutOfSpaceErr)
+	assert.False(t, ht.Contains("overflow"))
+}
+
+func TestMustGet(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	mustInsert(t, ht, "a", 1)
+	assert.Equal(t, 1, ht.MustGet("a"))
+	assert.PanicsWithValue(t, "elastichash: key b not found", func() { ht.MustGet("b") })
+}
+
+func TestGetWithDefault(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	mustInsert(t, ht, "a", 1)
+	mustInsert(t, ht, "zero", 0)
+	assert.Equal(t, 1, ht.GetWithDefault("a", -1))
+	assert.Equal(t, 0, ht.GetWithDefault("zero", -1), "a stored zero is not a miss")
+	assert.Equal(t, -1, ht.GetWithDefault("missing", -1))
+}
+
+func TestUpdate(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	mustInsert(t, ht, "key1", 1)
+	mustInsert(t, ht, "key2", 2)
+	require.True(t, ht.Delete("key2"))
+
+	assert.True(t, ht.Update("key1", 10))
+	v, ok := ht.Get("key1")
+	assert.True(t, ok)
+	assert.Equal(t, 10, v)
+
+	assert.False(t, ht.Update("key2", 20), "a deleted key should not be updated")
+	assert.False(t, ht.Update("key3", 30))
+	assert.False(t, ht.Contains("key2"))
+	assert.False(t, ht.Contains("key3"))
+	assert.Equal(t, 1, ht.Len())
+}
+
+func TestFreeze(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.1, elastichash.WithAutoGrow())
+	for i := range 20 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	assert.False(t, ht.Frozen())
+	ht.Freeze()
+	assert.True(t, ht.Frozen())
+
+	v, ok := ht.Get("key3")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.True(t, ht.Contains("key4"))
+	assert.Equal(t, 20, ht.Count(nil))
+	assert.Len(t, ht.Keys(), 20)
+
+	_, err := ht.Insert("key3", 30)
+	assert.ErrorIs(t, err, elastichash.FrozenErr)
+	_, err = ht.Insert("new", 1)
+	assert.ErrorIs(t, err, elastichash.FrozenErr)
+	_, _, err = ht.Swap("key3", 30)
+	assert.ErrorIs(t, err, elastichash.FrozenErr)
+	_, _, err = ht.GetOrInsert("new", 1)
+	assert.ErrorIs(t, err, elastichash.FrozenErr)
+	assert.ErrorIs(t, ht.Resize(200), elastichash.FrozenErr)
+	assert.ErrorIs(t, ht.Reserve(1000), elastichash.FrozenErr)
+	other := elastichash.NewHashTable[string, int](10, 0.1)
+	mustInsert(t, other, "other", 1)
+	assert.ErrorIs(t, ht.Merge(other), elastichash.FrozenErr)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"capacity": 10, "delta": 0.1, "entries": {}}`), ht), elastichash.FrozenErr)
+
+	for name, write := range map[string]func(){
+		"Delete":           func() { ht.Delete("key1") },
+		"Delete missing":   func() { ht.Delete("missing") },
+		"Pop":              func() { ht.Pop("key1") },
+		"Update":           func() { ht.Update("key1", 10) },
+		"CompareAndSwap":   func() { ht.CompareAndSwap("key1", 1, 10) },
+		"CompareAndDelete": func() { ht.CompareAndDelete("key1", 1) },
+		"Apply":            func() { ht.Apply(func(_ string, v int) int { return v }) },
+		"Reset":            func() { ht.Reset() },
+		"Compact":          func() { ht.Compact() },
+		"ShrinkToFit":      func() { ht.ShrinkToFit() },
+	} {
+		assert.PanicsWithValue(t, elastichash.FrozenErr, write, name)
+	}
+	assert.Equal(t, 20, ht.Len())
+	assert.Equal(t, 1, ht.MustGet("key1"), "no write should have gone through")
+
+	thawed := ht.Clone()
+	assert.False(t, thawed.Frozen())
+	mustInsert(t, thawed, "new", 1)
+	assert.False(t, ht.Contains("new"))
+}
+
+func TestApply(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.1)
+	for i := range 50 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	for i := range 10 {
+		require.True(t, ht.Delete(fmt.Sprintf("key%d", i)))
+	}
+	occupancies := ht.LevelOccupancies()
+
+	calls := 0
+	ht.Apply(func(_ string, v int) int {
+		calls++
+		return v + 1
+	})
+	assert.Equal(t, 40, calls, "deleted entries should be skipped")
+	for i := 10; i < 50; i++ {
+		v, ok := ht.Get(fmt.Sprintf("key%d", i))
+		require.True(t, ok)
+		assert.Equal(t, i+1, v)
+	}
+	assert.Equal(t, occupancies, ht.LevelOccupancies(), "entries should not move")
+}
+
+func TestReset(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.1, elastichash.WithC(2))
+	for i := range 80 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	require.True(t, ht.Delete("key0"))
+
+	ht.Reset()
+	assert.Zero(t, ht.Len())
+	assert.Equal(t, 100, ht.Cap())
+	assert.Equal(t, 2.0, ht.Stats().C)
+	assert.Zero(t, ht.Tombstones())
+	for i := range 80 {
+		assert.False(t, ht.Contains(fmt.Sprintf("key%d", i)))
+	}
+
+	for i := range ht.MaxLen() {
+		mustInsert(t, ht, fmt.Sprintf("again%d", i), i)
+	}
+	assert.Equal(t, ht.MaxLen(), ht.Len(), "a reset table fills back to MaxLen")
+}
+
+func TestLenAndCap(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	assert.Equal(t, 0, ht.Len())
+	assert.Equal(t, 10, ht.Cap())
+
+	mustInsert(t, ht, "key1", 1)
+	mustInsert(t, ht, "key2", 2)
+	assert.Equal(t, 2, ht.Len())
+
+	mustInsert(t, ht, "key1", 3)
+	assert.Equal(t, 2, ht.Len(), "replacing a value should not change Len")
+
+	require.True(t, ht.Delete("key1"))
+	assert.Equal(t, 1, ht.Len())
+	assert.False(t, ht.Delete("key1"))
+	assert.Equal(t, 1, ht.Len())
+	assert.Equal(t, 10, ht.Cap())
+}
+
+func TestKeys(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](200, 0.1)
+	assert.Empty(t, ht.Keys())
+
+	expected := []int{}
+	for i := range 100 {
+		mustInsert(t, ht, i, i)
+		if i%3 == 0 {
+			require.True(t, ht.Delete(i))
+		} else {
+			expected = append(expected, i)
+		}
+	}
+	keys := ht.Keys()
+	assert.Len(t, keys, ht.Len())
+	assert.ElementsMatch(t, expected, keys)
+}
+
+func TestValues(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](20, 0.1)
+	assert.Empty(t, ht.Values())
+
+	mustInsert(t, ht, "a", 1)
+	mustInsert(t, ht, "b", 2)
+	mustInsert(t, ht, "c", 2)
+	mustInsert(t, ht, "d", 3)
+	require.True(t, ht.Delete("d"))
+
+	values := ht.Values()
+	assert.Len(t, values, ht.Len())
+	assert.ElementsMatch(t, []int{1, 2, 2}, values)
+}
+
+func TestRange(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](50, 0.1)
+	for i := 1; i <= 10; i++ {
+		mustInsert(t, ht, i, i)
+	}
+
+	sum := 0
+	ht.Range(func(_ int, v int) bool {
+		sum += v
+		return true
+	})
+	assert.Equal(t, 55, sum)
+
+	calls := 0
+	ht.Range(func(int, int) bool {
+		calls++
+		return calls < 3
+	})
+	assert.Equal(t, 3, calls, "Range should stop once f returns false")
+}
+
+func TestCount(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.1)
+	for i := range 50 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	require.True(t, ht.Delete("key49"))
+	assert.Equal(t, 9, ht.Count(func(_ string, v int) bool { return v > 39 }))
+	assert.Zero(t, ht.Count(func(_ string, v int) bool { return v > 100 }))
+	assert.Equal(t, 49, ht.Count(nil))
+}
+
+func TestAll(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](50, 0.1)
+	expected := map[string]int{"a": 1, "b": 2, "c": 3}
+	for k, v := range expected {
+		mustInsert(t, ht, k, v)
+	}
+
+	got := map[string]int{}
+	for k, v := range ht.All() {
+		got[k] = v
+	}
+	assert.Equal(t, expected, got)
+
+	n := 0
+	for range ht.All() {
+		n++
+		break
+	}
+	assert.Equal(t, 1, n)
+}
+
+func TestAutoGrow(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](4, 0.1, elastichash.WithAutoGrow())
+	for i := range 1000 {
+		mustInsert(t, ht, i, i*i)
+	}
+	assert.Equal(t, 1000, ht.Len())
+	assert.GreaterOrEqual(t, ht.Cap(), 1000)
+	for i := range 1000 {
+		v, ok := ht.Get(i)
+		require.True(t, ok, "key %d should survive growth", i)
+		assert.Equal(t, i*i, v)
+	}
+
+	fixed := elastichash.NewHashTable[int, int](4, 0.1)
+	for i := range fixed.MaxLen() {
+		mustInsert(t, fixed, i, i)
+	}
+	_, err := fixed.Insert(100, 100)
+	assert.ErrorIs(t, err, elastichash.OutOfSpaceErr, "tables without WithAutoGrow keep a fixed capacity")
+	assert.Equal(t, 4, fixed.Cap())
+}
+
+func TestResize(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](20, 0.1)
+	for i := range 18 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	check := func(n int) {
+		t.Helper()
+		require.Equal(t, n, ht.Len())
+		for i := range n {
+			v, ok := ht.Get(fmt.Sprintf("key%d", i))
+			require.True(t, ok)
+			assert.Equal(t, i, v)
+		}
+	}
+
+	require.NoError(t, ht.Resize(1000))
+	assert.Equal(t, 1000, ht.Cap())
+	check(18)
+	for i := 18; i < 500; i++ {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+
+	for i := 100; i < 500; i++ {
+		require.True(t, ht.Delete(fmt.Sprintf("key%d", i)))
+	}
+	require.NoError(t, ht.Resize(150))
+	assert.Equal(t, 150, ht.Cap())
+	assert.Zero(t, ht.Tombstones(), "resizing drops tombstones")
+	check(100)
+
+	assert.ErrorIs(t, ht.Resize(100), elastichash.OutOfSpaceErr, "100 items exceed MaxLen of 90")
+	assert.Error(t, ht.Resize(0))
+	assert.Equal(t, 150, ht.Cap(), "a failed resize leaves the table as it was")
+	check(100)
+}
+
+func TestReserve(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](10, 0.1, elastichash.WithAutoGrow())
+	require.NoError(t, ht.Reserve(5))
+	assert.Equal(t, 10, ht.Cap(), "room for 5 already exists")
+	assert.Zero(t, ht.Stats().Resizes)
+
+	require.NoError(t, ht.Reserve(1000))
+	assert.GreaterOrEqual(t, ht.MaxLen(), 1000)
+	resizes := ht.Stats().Resizes
+	assert.Equal(t, 1, resizes)
+	for i := range 1000 {
+		mustInsert(t, ht, i, i)
+	}
+	assert.Equal(t, resizes, ht.Stats().Resizes, "no resize after Reserve")
+
+	unreserved := elastichash.NewHashTable[int, int](10, 0.1, elastichash.WithAutoGrow())
+	for i := range 1000 {
+		mustInsert(t, unreserved, i, i)
+	}
+	assert.Greater(t, unreserved.Stats().Resizes, 1)
+
+	assert.ErrorIs(t, ht.Reserve(math.MaxInt), elastichash.OutOfSpaceErr)
+	assert.Equal(t, resizes, ht.Stats().Resizes, "a failed Reserve leaves the table alone")
+	assert.Equal(t, 1000, ht.Len())
+}
+
+func TestShrinkToFit(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](1200, 0.1)
+	for i := range 1000 {
+		mustInsert(t, ht, i, i)
+	}
+	for i := range 950 {
+		require.True(t, ht.Delete(i))
+	}
+	ht.ShrinkToFit()
+	assert.Less(t, ht.Cap(), 100)
+	assert.GreaterOrEqual(t, ht.MaxLen(), 50)
+	assert.Equal(t, 50, ht.Len())
+	assert.Zero(t, ht.Tombstones())
+	for i := 950; i < 1000; i++ {
+		v, ok := ht.Get(i)
+		require.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+
+	capacity, resizes := ht.Cap(), ht.Stats().Resizes
+	ht.ShrinkToFit()
+	assert.Equal(t, capacity, ht.Cap())
+	assert.Equal(t, resizes, ht.Stats().Resizes, "an already minimal table is left alone")
+}
+
+func TestCustomHasher(t *testing.T) {
+	type point struct{ x, y int }
+	// Every key collides, so lookups rely entirely on the probe sequence.
+	collide := func(point) uint64 { return 7 }
+	ht := elastichash.NewHashTableWithHasher[point, int](100, 0.1, collide)
+	for i := range 20 {
+		mustInsert(t, ht, point{i, -i}, i)
+	}
+	for i := range 20 {
+		v, ok := ht.Get(point{i, -i})
+		require.True(t, ok, "point %d should be retrievable", i)
+		assert.Equal(t, i, v)
+	}
+	assert.False(t, ht.Contains(point{1, 1}))
+	require.True(t, ht.Delete(point{3, -3}))
+	assert.False(t, ht.Contains(point{3, -3}))
+	assert.True(t, ht.Contains(point{4, -4}))
+}
+
+func TestCustomHasherDefaults(t *testing.T) {
+	ht := elastichash.NewHashTableWithHasher[string, int](10, 0.1, nil)
+	mustInsert(t, ht, "key1", 1)
+	assert.True(t, ht.Contains("key1"))
+
+	assert.Panics(t, func() {
+		elastichash.NewHashTableWithHasher[struct{ a int }, int](10, 0.1, nil)
+	})
+}
+
+func TestSeededLayoutsMatch(t *testing.T) {
+	seed := maphash.MakeSeed()
+	a := elastichash.NewHashTableWithSeed[string, int](100, 0.1, seed)
+	b := elastichash.NewHashTableWithSeed[string, int](100, 0.1, seed)
+	for i := range 80 {
+		mustInsert(t, a, fmt.Sprintf("key%d", i), i)
+		mustInsert(t, b, fmt.Sprintf("key%d", i), i)
+	}
+	assert.Equal(t, a.String(), b.String())
+	assert.Equal(t, a.LevelOccupancies(), b.LevelOccupancies())
+}
+
+func TestUnsignedKeys(t *testing.T) {
+	ht := elastichash.NewHashTable[uint64, int](100, 0.1)
+	keys := []uint64{0, 1, math.MaxUint64, math.MaxUint64 - 1, math.MaxUint64 / 2, 1 << 63}
+	for i, k := range keys {
+		mustInsert(t, ht, k, i)
+	}
+	for i, k := range keys {
+		v, ok := ht.Get(k)
+		require.True(t, ok, "key %d should be retrievable", k)
+		assert.Equal(t, i, v)
+	}
+	assert.False(t, ht.Contains(2))
+	assert.NotEqual(t, elastichash.HashKey[uint64](math.MaxUint64), elastichash.HashKey[uint64](0))
+
+	type port uint16
+	ports := elastichash.NewHashTable[port, string](10, 0.1)
+	mustInsert(t, ports, 443, "https")
+	mustInsert(t, ports, 80, "http")
+	v, ok := ports.Get(443)
+	assert.True(t, ok)
+	assert.Equal(t, "https", v)
+	assert.NotEqual(t, elastichash.HashKey[port](443), elastichash.HashKey[port](80))
+}
+
+func TestSignedAndFloatKeys(t *testing.T) {
+	ints := elastichash.NewHashTable[int64, int](200, 0.1)
+	for i := int64(-50); i < 50; i++ {
+		mustInsert(t, ints, i*1_000_003, int(i))
+	}
+	mustInsert(t, ints, math.MinInt64, -1000)
+	mustInsert(t, ints, math.MaxInt64, 1000)
+	for i := int64(-50); i < 50; i++ {
+		v, ok := ints.Get(i * 1_000_003)
+		require.True(t, ok, "key %d should be retrievable", i*1_000_003)
+		assert.Equal(t, int(i), v)
+	}
+	v, ok := ints.Get(math.MinInt64)
+	assert.True(t, ok)
+	assert.Equal(t, -1000, v)
+	assert.NotEqual(t, elastichash.HashKey[int64](-1), elastichash.HashKey[int64](1))
+
+	small := elastichash.NewHashTable[int8, int](10, 0.1)
+	mustInsert(t, small, -128, 1)
+	assert.True(t, small.Contains(-128))
+	assert.False(t, small.Contains(127))
+
+	floats := elastichash.NewHashTable[float64, int](200, 0.1)
+	for i := range 100 {
+		mustInsert(t, floats, float64(i)*-1.5, i)
+	}
+	mustInsert(t, floats, math.Inf(1), 500)
+	for i := range 100 {
+		v, ok := floats.Get(float64(i) * -1.5)
+		require.True(t, ok, "key %v should be retrievable", float64(i)*-1.5)
+		assert.Equal(t, i, v)
+	}
+	assert.True(t, floats.Contains(math.Inf(1)))
+	assert.False(t, floats.Contains(math.Inf(-1)))
+
+	// -0.0 == +0.0, so they are the same key.
+	negZero := math.Copysign(0, -1)
+	assert.Equal(t, elastichash.HashKey(0.0), elastichash.HashKey(negZero))
+	replaced, err := floats.Insert(negZero, -1)
+	require.NoError(t, err)
+	assert.True(t, replaced)
+	v, _ = floats.Get(0.0)
+	assert.Equal(t, -1, v)
+
+	f32 := elastichash.NewHashTable[float32, int](10, 0.1)
+	mustInsert(t, f32, 2.5, 1)
+	assert.True(t, f32.Contains(2.5))
+	assert.Equal(t, elastichash.HashKey[float32](0), elastichash.HashKey(float32(negZero)))
+}
+
+type pair struct{ a, b int }
+
+func (p pair) HashCode() uint64 {
+	return elastichash.HashKey(p.a)*31 + elastichash.HashKey(p.b)
+}
+
+func TestHashableKeys(t *testing.T) {
+	ht := elastichash.NewHashableHashTable[pair, string](100, 0.1)
+	for i := range 30 {
+		mustInsert(t, ht, pair{i, i + 1}, fmt.Sprint(i))
+	}
+	for i := range 30 {
+		v, ok := ht.Get(pair{i, i + 1})
+		require.True(t, ok, "pair %d should be retrievable", i)
+		assert.Equal(t, fmt.Sprint(i), v)
+	}
+	assert.False(t, ht.Contains(pair{1, 0}))
+}
+
+func TestStringIsValidJSON(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	assert.Equal(t, "{}", ht.String())
+
+	mustInsert(t, ht, "a", 1)
+	mustInsert(t, ht, `quo"te`, 2)
+	mustInsert(t, ht, `back\slash`, 3)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(ht.String()), &decoded))
+	assert.Equal(t, map[string]any{"a": 1.0, `quo"te`: 2.0, `back\slash`: 3.0}, decoded)
+
+	strs := elastichash.NewHashTable[int, string](10, 0.1)
+	mustInsert(t, strs, 7, `say "hi"`)
+	decoded = nil
+	require.NoError(t, json.Unmarshal([]byte(strs.String()), &decoded))
+	assert.Equal(t, map[string]any{"7": `say "hi"`}, decoded)
+}
+
+func mustInsert[K comparable, V any](t *testing.T, ht *elastichash.HashTable[K, V], key K, value V) {
+	t.Helper()
+	_, err := ht.Insert(key, value)
+	require.NoError(t, err)
+}
+
+func nonZero(counts []int) []int {
+	var out []int
+	for _, c := range counts {
+		if c != 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func BenchmarkGet(b *testing.B) {
+	ht := elastichash.NewHashTable[int, int](100_000, 0.1)
+	for i := range 80_000 {
+		if _, err := ht.Insert(i, i); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ResetTimer()
+	for i := range b.N {
+		ht.Get(i % 80_000)
+	}
+}
+
+func BenchmarkInsert(b *testing.B) {
+	b.ReportAllocs()
+	for range b.N {
+		ht := elastichash.NewHashTable[int, int](100_000, 0.1)
+		for i := range 80_000 {
+			if _, err := ht.Insert(i, i); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkResize(b *testing.B) {
+	ht := elastichash.NewHashTable[string, int](125_000, 0.1)
+	for i := range 100_000 {
+		if _, err := ht.Insert(fmt.Sprintf("key%d", i), i); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ResetTimer()
+	for i := range b.N {
+		if err := ht.Resize(250_000 - i%2*125_000); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHashKeyInt(b *testing.B) {
+	b.ReportAllocs()
+	for i := range b.N {
+		elastichash.HashKey(i)
+	}
+}
+
+func BenchmarkHashKeyString(b *testing.B) {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		elastichash.HashKey(keys[i%len(keys)])
+	}
+}
+
+func BenchmarkGetMiss(b *testing.B) {
+	ht := elastichash.NewHashTable[int, int](100_000, 0.1)
+	for i := range 80_000 {
+		if _, err := ht.Insert(i, i); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		ht.Get(-1 - i%80_000)
+	}
+}
+
 type operation struct {
 	opType string
 	key    string
 	value  int
 }
+
+func TestDelete(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	mustInsert(t, ht, "key1", 1)
+	mustInsert(t, ht, "key2", 2)
+	mustInsert(t, ht, "key3", 3)
+
+	assert.True(t, ht.Delete("key2"))
+	_, ok := ht.Get("key2")
+	assert.False(t, ok)
+	assert.False(t, ht.Delete("key2"), "deleting an absent key should report false")
+
+	for key, expected := range map[string]int{"key1": 1, "key3": 3} {
+		v, ok := ht.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, expected, v)
+	}
+	assert.Equal(t, 1, ht.Tombstones())
+}
+
+func TestClone(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.1)
+	for i := range 50 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	require.True(t, ht.Delete("key0"))
+
+	clone := ht.Clone()
+	assert.Equal(t, ht.Len(), clone.Len())
+	assert.Equal(t, ht.Stats(), clone.Stats())
+
+	require.True(t, clone.Delete("key1"))
+	require.True(t, clone.Update("key2", -2))
+	mustInsert(t, clone, "key0", 0)
+	mustInsert(t, clone, "new", 100)
+
+	assert.Equal(t, 49, ht.Len())
+	assert.False(t, ht.Contains("key0"))
+	assert.False(t, ht.Contains("new"))
+	for i := 1; i < 50; i++ {
+		v, ok := ht.Get(fmt.Sprintf("key%d", i))
+		require.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+
+	v, ok := clone.Get("key2")
+	require.True(t, ok)
+	assert.Equal(t, -2, v)
+	assert.False(t, clone.Contains("key1"))
+	assert.Equal(t, 50, clone.Len())
+}
+
+func TestMerge(t *testing.T) {
+	a := elastichash.NewHashTable[string, int](100, 0.1)
+	b := elastichash.NewHashTable[string, int](100, 0.1)
+	for i := range 20 {
+		mustInsert(t, a, fmt.Sprintf("a%d", i), i)
+		mustInsert(t, b, fmt.Sprintf("b%d", i), -i)
+	}
+	require.NoError(t, a.Merge(b))
+	assert.Equal(t, 40, a.Len())
+	assert.Equal(t, 20, b.Len(), "the source is left alone")
+	for i := range 20 {
+		v, ok := a.Get(fmt.Sprintf("b%d", i))
+		require.True(t, ok)
+		assert.Equal(t, -i, v)
+	}
+
+	overlap := elastichash.NewHashTable[string, int](10, 0.1)
+	mustInsert(t, overlap, "a0", 100)
+	mustInsert(t, overlap, "c", 3)
+	require.NoError(t, a.Merge(overlap))
+	assert.Equal(t, 41, a.Len())
+	v, _ := a.Get("a0")
+	assert.Equal(t, 100, v, "the incoming value wins")
+
+	small := elastichash.NewHashTable[string, int](10, 0.1)
+	assert.ErrorIs(t, small.Merge(a), elastichash.OutOfSpaceErr)
+	assert.Equal(t, small.MaxLen(), small.Len())
+}
+
+func TestEqual(t *testing.T) {
+	a := elastichash.NewHashTable[string, int](20, 0.1)
+	b := elastichash.NewHashTable[string, int](1000, 0.2)
+	for i := range 15 {
+		mustInsert(t, a, fmt.Sprintf("key%d", i), i)
+		mustInsert(t, b, fmt.Sprintf("key%d", 14-i), 14-i)
+	}
+	assert.True(t, a.Equal(b, nil), "capacity and insertion order do not matter")
+	assert.True(t, b.Equal(a, func(x, y int) bool { return x == y }))
+
+	require.True(t, b.Update("key7", 70))
+	assert.False(t, a.Equal(b, nil))
+	assert.True(t, a.Equal(b, func(x, y int) bool { return x == y || 10*x == y }))
+
+	require.True(t, b.Delete("key7"))
+	assert.False(t, a.Equal(b, nil))
+	mustInsert(t, b, "other", 7)
+	assert.False(t, a.Equal(b, nil), "same length, different keys")
+
+	slices := elastichash.NewHashTable[string, []int](10, 0.1)
+	assert.Panics(t, func() { slices.Equal(slices, nil) })
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	mustInsert(t, ht, "a", 1)
+
+	assert.True(t, ht.CompareAndSwap("a", 1, 2))
+	assert.Equal(t, 2, ht.MustGet("a"))
+
+	assert.False(t, ht.CompareAndSwap("a", 1, 3), "a stale old value should not swap")
+	assert.Equal(t, 2, ht.MustGet("a"))
+
+	assert.False(t, ht.CompareAndSwap("missing", 0, 1))
+	assert.False(t, ht.Contains("missing"), "a missing key is not inserted")
+	assert.Equal(t, 1, ht.Len())
+}
+
+func TestCompareAndDelete(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	mustInsert(t, ht, "a", 1)
+
+	assert.False(t, ht.CompareAndDelete("a", 2), "a mismatched value should not delete")
+	assert.Equal(t, 1, ht.MustGet("a"))
+	assert.False(t, ht.CompareAndDelete("missing", 0))
+
+	assert.True(t, ht.CompareAndDelete("a", 1))
+	assert.False(t, ht.Contains("a"))
+	assert.Zero(t, ht.Len())
+	assert.False(t, ht.CompareAndDelete("a", 1), "a deleted key is absent")
+
+	slices := elastichash.NewHashTable[string, []int](10, 0.1)
+	assert.PanicsWithValue(t, "elastichash: CompareAndDelete needs a comparator for value type []int", func() {
+		slices.CompareAndDelete("a", nil)
+	})
+}
+
+func TestDeleteMany(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.1)
+	for i := range 20 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	deleted := ht.DeleteMany([]string{"key1", "key2", "missing", "key1", "key19"})
+	assert.Equal(t, 3, deleted, "absent and repeated keys should not count")
+	assert.Equal(t, 17, ht.Len())
+	assert.False(t, ht.Contains("key2"))
+	assert.True(t, ht.Contains("key3"))
+	assert.Zero(t, ht.DeleteMany(nil))
+}
+
+func TestPop(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	mustInsert(t, ht, "a", 1)
+	mustInsert(t, ht, "b", 2)
+
+	v, ok := ht.Pop("a")
+	require.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.False(t, ht.Contains("a"))
+	assert.Equal(t, 1, ht.Len())
+	assert.Equal(t, 1, ht.Tombstones())
+
+	v, ok = ht.Pop("a")
+	assert.False(t, ok)
+	assert.Zero(t, v)
+	assert.Equal(t, 1, ht.Len())
+}
+
+func TestChurnKeepsProbesBounded(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](1000, 0.1)
+	r := rand.New(rand.NewPCG(1, 2))
+	// window runs 10k random inserts and deletes over 1500 keys, enough to
+	// keep the table close to MaxLen, and returns the mean Get probe length.
+	window := func() float64 {
+		probes := 0
+		for range 10_000 {
+			key := r.IntN(1500)
+			if r.IntN(2) == 0 {
+				_, _ = ht.Insert(key, key)
+			} else {
+				ht.Delete(key)
+			}
+			_, _, n := ht.GetWithProbes(r.IntN(1500))
+			probes += n
+		}
+		return float64(probes) / 10_000
+	}
+	first := window()
+	for range 9 {
+		assert.Less(t, window(), 1.5*first)
+		assert.LessOrEqual(t, ht.Tombstones(), ht.Cap()/4)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](1000, 0.1)
+	for i := range 800 {
+		mustInsert(t, ht, i, i)
+	}
+	for i := 0; i < 800; i += 4 {
+		require.True(t, ht.Delete(i))
+	}
+	require.Equal(t, 200, ht.Tombstones(), "below the automatic compaction point")
+	missProbes := func() int {
+		total := 0
+		for i := range 100 {
+			_, ok, n := ht.GetWithProbes(-1 - i)
+			require.False(t, ok)
+			total += n
+		}
+		return total
+	}
+	before := missProbes()
+
+	ht.Compact()
+	assert.Zero(t, ht.Tombstones())
+	assert.Equal(t, 1000, ht.Cap())
+	assert.Equal(t, 600, ht.Len())
+	for i := range 800 {
+		assert.Equal(t, i%4 != 0, ht.Contains(i))
+	}
+	assert.Less(t, missProbes(), before)
+}
+
+func TestDeleteReusesSlot(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	mustInsert(t, ht, "key1", 1)
+	require.True(t, ht.Delete("key1"))
+	require.Equal(t, 1, ht.Tombstones())
+
+	mustInsert(t, ht, "key1", 2)
+	assert.Equal(t, 0, ht.Tombstones(), "re-inserting a deleted key should reuse its tombstone")
+	v, ok := ht.Get("key1")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+}
+
+func TestHashKeyDeterministic(t *testing.T) {
+	assert.Equal(t, elastichash.HashKey("key1"), elastichash.HashKey("key1"))
+	assert.Equal(t, elastichash.HashKey(42), elastichash.HashKey(42))
+}
+
+func TestHashKeyDoesNotAllocate(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		elastichash.HashKey(123456789)
+		elastichash.HashKey(int64(-42))
+		elastichash.HashKey(uint64(7))
+		elastichash.HashKey(2.5)
+		elastichash.HashKey("key")
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestManyKeysRetrievable(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](2000, 0.1)
+	for i := range 1000 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	for i := range 1000 {
+		v, ok := ht.Get(fmt.Sprintf("key%d", i))
+		require.True(t, ok, "key%d should be retrievable", i)
+		assert.Equal(t, i, v)
+	}
+}
+
+func TestFillToMaxLen(t *testing.T) {
+	for _, capacity := range []int{1, 3, 10, 100, 1000} {
+		t.Run(fmt.Sprintf("capacity %d", capacity), func(t *testing.T) {
+			ht := elastichash.NewHashTable[string, int](capacity, 0.1)
+			for i := range ht.MaxLen() {
+				mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+			}
+			_, err := ht.Insert("overflow", -1)
+			assert.ErrorIs(t, err, elastichash.OutOfSpaceErr)
+
+			occupancies := ht.LevelOccupancies()
+			sum := 0
+			for _, o := range occupancies {
+				sum += o
+			}
+			assert.Equal(t, ht.MaxLen(), sum)
+			assert.Positive(t, occupancies[len(occupancies)-1], "spillover should land in the last level")
+			for i := range ht.MaxLen() {
+				v, ok := ht.Get(fmt.Sprintf("key%d", i))
+				require.True(t, ok)
+				assert.Equal(t, i, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package elastichash
+
+// Iterator walks a table's live entries one Next at a time, for callers that
+// need to pause between entries; Range and All cover the push-style case.
+// Any change to the table other than Update or Apply invalidates it.
+type Iterator[K comparable, V any] struct {
+	ht    *HashTable[K, V]
+	level int
+	// idx is the slot Key and Value read, or -1 before the first Next.
+	idx int
+}
+
+// Iterator returns an Iterator positioned before the first live entry.
+func (ht *HashTable[K, V]) Iterator() *Iterator[K, V] {
+	return &Iterator[K, V]{ht: ht, idx: -1}
+}
+
+// Next advances to the next live entry and reports whether there was one.
+func (it *Iterator[K, V]) Next() bool {
+	levels := it.ht.levels
+	for it.level < len(levels) {
+		for it.idx++; it.idx < len(levels[it.level]); it.idx++ {
+			if levels[it.level][it.idx].state == occupied {
+				return true
+			}
+		}
+		it.level++
+		it.idx = -1
+	}
+	return false
+}
+
+// Key returns the current entry's key. It must only be called after Next
+// has returned true.
+func (it *Iterator[K, V]) Key() K {
+	return it.ht.levels[it.level][it.idx].key
+}
+
+// Value returns the current entry's value. It must only be called after
+// Next has returned true.
+func (it *Iterator[K, V]) Value() V {
+	return it.ht.levels[it.level][it.idx].value
+}
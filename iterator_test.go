@@ -0,0 +1,59 @@
+package elastichash_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestIterator(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](200, 0.1)
+	for i := range 100 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	for i := range 20 {
+		require.True(t, ht.Delete(fmt.Sprintf("key%d", i*5)))
+	}
+
+	seen := make(map[string]int)
+	it := ht.Iterator()
+	for it.Next() {
+		key := it.Key()
+		_, dup := seen[key]
+		assert.False(t, dup, "%s yielded twice", key)
+		seen[key] = it.Value()
+	}
+	assert.Equal(t, ht.ToMap(), seen)
+	assert.False(t, it.Next(), "an exhausted iterator stays exhausted")
+
+	empty := elastichash.NewHashTable[string, int](10, 0.1)
+	assert.False(t, empty.Iterator().Next())
+}
+
+func TestIteratorPauses(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](50, 0.1)
+	for i := range 30 {
+		mustInsert(t, ht, i, i)
+	}
+	it := ht.Iterator()
+	next := func() (int, bool) {
+		if !it.Next() {
+			return 0, false
+		}
+		return it.Key(), true
+	}
+
+	sum := 0
+	for {
+		key, ok := next()
+		if !ok {
+			break
+		}
+		sum += key
+	}
+	assert.Equal(t, 29*30/2, sum)
+}
@@ -0,0 +1,131 @@
+package elastichash
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// jsonTable is the JSON form of a HashTable. Entries is an object when K is a
+// string kind and an array of jsonEntry otherwise.
+type jsonTable struct {
+	Capacity int             `json:"capacity"`
+	Delta    float64         `json:"delta"`
+	Entries  json.RawMessage `json:"entries"`
+}
+
+type jsonEntry[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// MarshalJSON encodes the table's capacity, delta and live entries. It fails
+// for a capacity above 1<<24, which UnmarshalJSON would reject.
+func (ht *HashTable[K, V]) MarshalJSON() ([]byte, error) {
+	if err := ht.checkEncodable(); err != nil {
+		return nil, err
+	}
+	var entries any
+	if reflect.TypeFor[K]().Kind() == reflect.String {
+		m := make(map[K]V, ht.Len())
+		ht.Range(func(key K, value V) bool {
+			m[key] = value
+			return true
+		})
+		entries = m
+	} else {
+		pairs := make([]jsonEntry[K, V], 0, ht.Len())
+		ht.Range(func(key K, value V) bool {
+			pairs = append(pairs, jsonEntry[K, V]{key, value})
+			return true
+		})
+		entries = pairs
+	}
+	raw, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonTable{Capacity: ht.capacity, Delta: ht.delta, Entries: raw})
+}
+
+// UnmarshalJSON rebuilds the table from MarshalJSON output: the level
+// structure is re-derived from the encoded capacity and every entry is
+// reinserted. A zero HashTable can be used as the target as long as K is a
+// type HashKey supports; an existing table keeps its hasher and options. A
+// capacity above 1<<24 is rejected with InvalidFormatErr.
+func (ht *HashTable[K, V]) UnmarshalJSON(data []byte) error {
+	var jt jsonTable
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return err
+	}
+	var pairs []jsonEntry[K, V]
+	if raw := bytes.TrimSpace(jt.Entries); len(raw) > 0 && raw[0] == '{' {
+		var m map[K]V
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return err
+		}
+		for key, value := range m {
+			pairs = append(pairs, jsonEntry[K, V]{key, value})
+		}
+	} else if err := json.Unmarshal(raw, &pairs); err != nil {
+		return err
+	}
+
+	next, err := ht.decodeTarget(jt.Capacity, jt.Delta)
+	if err != nil {
+		return err
+	}
+	for _, p := range pairs {
+		if _, _, _, err := next.insert(p.Key, p.Value); err != nil {
+			return fmt.Errorf("elastichash: restoring key %v: %w", p.Key, err)
+		}
+	}
+	ht.adopt(next)
+	return nil
+}
+
+// maxSerializedCapacity is the largest capacity the JSON, gob, binary and
+// MessagePack forms carry. Decoders reject anything larger so that a few
+// bytes of corrupt or hostile input cannot demand gigabytes of slots, and
+// encoders refuse to write what the decoders would reject. Export and Import
+// work in memory and are not limited. It is a variable so tests can lower it.
+var maxSerializedCapacity = 1 << 24
+
+// checkEncodable fails if ht is too large for its encoding to be decoded.
+func (ht *HashTable[K, V]) checkEncodable() error {
+	if ht.capacity > maxSerializedCapacity {
+		return fmt.Errorf("elastichash: capacity %d exceeds the largest encodable capacity %d", ht.capacity, maxSerializedCapacity)
+	}
+	return nil
+}
+
+// decodeTarget is importTarget for decoders of untrusted bytes: it also fails
+// with InvalidFormatErr when capacity exceeds maxSerializedCapacity.
+func (ht *HashTable[K, V]) decodeTarget(capacity int, delta float64) (*HashTable[K, V], error) {
+	if capacity > maxSerializedCapacity {
+		return nil, fmt.Errorf("elastichash: decoded capacity %d exceeds %d: %w", capacity, maxSerializedCapacity, InvalidFormatErr)
+	}
+	return ht.importTarget(capacity, delta)
+}
+
+// importTarget returns an empty table with the given capacity and delta to be
+// filled. It keeps ht's hasher and options, or NewHashTable's defaults when
+// ht is a zero HashTable, and fails with FrozenErr when ht is frozen.
+func (ht *HashTable[K, V]) importTarget(capacity int, delta float64) (*HashTable[K, V], error) {
+	if ht.frozen {
+		return nil, FrozenErr
+	}
+	if err := validateArgs(capacity, delta); err != nil {
+		return nil, err
+	}
+	if ht.levels == nil {
+		if !hashable[K]() {
+			return nil, fmt.Errorf("elastichash: no default hasher for key type %T", *new(K))
+		}
+		return newHashTable[K, V](capacity, delta, hashSeed, nil, nil), nil
+	}
+	next := ht.emptyCopy(capacity)
+	next.delta = delta
+	return next, nil
+}
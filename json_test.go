@@ -0,0 +1,93 @@
+package elastichash_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestJSONRoundTripStringKeys(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](50, 0.2)
+	for i := range 30 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	data, err := json.Marshal(ht)
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.IsType(t, map[string]any{}, raw["entries"], "string keys should encode as an object")
+
+	var loaded elastichash.HashTable[string, int]
+	require.NoError(t, json.Unmarshal(data, &loaded))
+	assert.Equal(t, 50, loaded.Cap())
+	assert.Equal(t, 30, loaded.Len())
+	for i := range 30 {
+		v, ok := loaded.Get(fmt.Sprintf("key%d", i))
+		require.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+}
+
+func TestJSONRoundTripIntKeys(t *testing.T) {
+	ht := elastichash.NewHashTable[int, string](50, 0.1)
+	for i := range 30 {
+		mustInsert(t, ht, i*7, fmt.Sprint(i))
+	}
+	data, err := json.Marshal(ht)
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.IsType(t, []any{}, raw["entries"], "non-string keys should encode as an array of pairs")
+
+	loaded := elastichash.NewHashTable[int, string](1, 0.5)
+	require.NoError(t, json.Unmarshal(data, loaded))
+	assert.Equal(t, 50, loaded.Cap())
+	assert.Equal(t, 30, loaded.Len())
+	for i := range 30 {
+		v, ok := loaded.Get(i * 7)
+		require.True(t, ok)
+		assert.Equal(t, fmt.Sprint(i), v)
+	}
+}
+
+func TestJSONUnmarshalErrors(t *testing.T) {
+	var ht elastichash.HashTable[string, int]
+	assert.Error(t, json.Unmarshal([]byte(`{"capacity": 10, "delta": 0.1, "entries": [1, 2]}`), &ht))
+
+	tooMany := `{"capacity": 2, "delta": 0.5, "entries": {"a": 1, "b": 2, "c": 3}}`
+	assert.ErrorIs(t, json.Unmarshal([]byte(tooMany), &ht), elastichash.OutOfSpaceErr)
+
+	assert.ErrorContains(t, json.Unmarshal([]byte(`{"capacity": 0, "delta": 0.1, "entries": {}}`), &ht), "capacity")
+	assert.ErrorContains(t, json.Unmarshal([]byte(`{"capacity": 10, "delta": 2, "entries": {}}`), &ht), "delta")
+
+	huge := `{"capacity": 9000000000000000000, "delta": 0.1, "entries": {}}`
+	assert.ErrorIs(t, json.Unmarshal([]byte(huge), &ht), elastichash.InvalidFormatErr)
+	assert.Equal(t, 0, ht.Cap())
+}
+
+func TestSerializedCapacityLimit(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.1)
+	mustInsert(t, ht, "key", 1)
+	data, err := json.Marshal(ht)
+	require.NoError(t, err)
+
+	restore := elastichash.SetMaxSerializedCapacity(64)
+	defer restore()
+	for name, encode := range map[string]func() error{
+		"MarshalJSON":    func() error { _, err := ht.MarshalJSON(); return err },
+		"GobEncode":      func() error { _, err := ht.GobEncode(); return err },
+		"MarshalBinary":  func() error { _, err := ht.MarshalBinary(); return err },
+		"MarshalMsgpack": func() error { _, err := ht.MarshalMsgpack(); return err },
+	} {
+		assert.ErrorContains(t, encode(), "largest encodable capacity 64", name)
+	}
+	var loaded elastichash.HashTable[string, int]
+	assert.ErrorIs(t, json.Unmarshal(data, &loaded), elastichash.InvalidFormatErr, "the decoder applies the same limit")
+}
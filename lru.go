@@ -0,0 +1,112 @@
+package elastichash
+
+import (
+	"container/list"
+	"errors"
+)
+
+// LRUHashTable is a bounded cache over a HashTable: instead of failing when
+// the table is full, Insert evicts the least recently used entry. Insert and
+// Get count as uses. It is not safe for concurrent use, and not even Get may
+// run alongside another call since it reorders the recency list.
+type LRUHashTable[K comparable, V any] struct {
+	ht *HashTable[K, *list.Element]
+	// recency holds lruEntry values, most recently used at the front.
+	recency *list.List
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func NewLRUHashTable[K ValidKey, V any](capacity int, delta float64, opts ...Option) *LRUHashTable[K, V] {
+	return &LRUHashTable[K, V]{
+		ht:      NewHashTable[K, *list.Element](capacity, delta, opts...),
+		recency: list.New(),
+	}
+}
+
+// Insert stores value under key and marks it most recently used, evicting
+// least recently used entries until the key fits. It reports whether key was
+// already present; an error is only possible for a table with no room at all.
+func (lt *LRUHashTable[K, V]) Insert(key K, value V) (replaced bool, err error) {
+	if el, ok := lt.ht.Get(key); ok {
+		el.Value = lruEntry[K, V]{key: lt.ht.normalized(key), value: value}
+		lt.recency.MoveToFront(el)
+		return true, nil
+	}
+	el := lt.recency.PushFront(lruEntry[K, V]{key: lt.ht.normalized(key), value: value})
+	for {
+		_, err = lt.ht.Insert(key, el)
+		if !errors.Is(err, OutOfSpaceErr) && !errors.Is(err, FailedToInsertErr) {
+			break
+		}
+		if !lt.evict() {
+			break
+		}
+	}
+	if err != nil {
+		lt.recency.Remove(el)
+	}
+	return false, err
+}
+
+// evict removes the least recently used entry other than the one just
+// pushed, and reports whether there was one.
+func (lt *LRUHashTable[K, V]) evict() bool {
+	back := lt.recency.Back()
+	if back == nil || back == lt.recency.Front() {
+		return false
+	}
+	lt.recency.Remove(back)
+	lt.ht.Delete(back.Value.(lruEntry[K, V]).key)
+	return true
+}
+
+// Get returns the value stored under key and marks it most recently used.
+func (lt *LRUHashTable[K, V]) Get(key K) (V, bool) {
+	el, ok := lt.ht.Get(key)
+	if !ok {
+		return *new(V), false
+	}
+	lt.recency.MoveToFront(el)
+	return el.Value.(lruEntry[K, V]).value, true
+}
+
+// Peek returns the value stored under key without marking it used.
+func (lt *LRUHashTable[K, V]) Peek(key K) (V, bool) {
+	el, ok := lt.ht.Get(key)
+	if !ok {
+		return *new(V), false
+	}
+	return el.Value.(lruEntry[K, V]).value, true
+}
+
+// Delete removes key and reports whether it was present.
+func (lt *LRUHashTable[K, V]) Delete(key K) bool {
+	el, ok := lt.ht.Pop(key)
+	if ok {
+		lt.recency.Remove(el)
+	}
+	return ok
+}
+
+// Keys returns every key from most to least recently used.
+func (lt *LRUHashTable[K, V]) Keys() []K {
+	keys := make([]K, 0, lt.recency.Len())
+	for el := lt.recency.Front(); el != nil; el = el.Next() {
+		keys = append(keys, el.Value.(lruEntry[K, V]).key)
+	}
+	return keys
+}
+
+// Len returns the number of live entries.
+func (lt *LRUHashTable[K, V]) Len() int {
+	return lt.ht.Len()
+}
+
+// Cap returns the configured capacity.
+func (lt *LRUHashTable[K, V]) Cap() int {
+	return lt.ht.Cap()
+}
@@ -0,0 +1,73 @@
+package elastichash_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestLRUHashTableEvictsLeastRecentlyUsed(t *testing.T) {
+	lt := elastichash.NewLRUHashTable[int, int](20, 0.1)
+	n := 0
+	for ; lt.Len() == n; n++ {
+		_, err := lt.Insert(n, n)
+		require.NoError(t, err)
+	}
+	// The insert that did not grow Len had to evict key 0.
+	full := lt.Len()
+	require.Equal(t, n-1, full)
+	_, ok := lt.Peek(0)
+	assert.False(t, ok)
+
+	// Touch the oldest remaining keys so they outlive the ones after them.
+	_, ok = lt.Get(1)
+	require.True(t, ok)
+	_, ok = lt.Get(2)
+	require.True(t, ok)
+
+	for i := range 3 {
+		replaced, err := lt.Insert(100+i, i)
+		require.NoError(t, err)
+		assert.False(t, replaced)
+	}
+	assert.Equal(t, full, lt.Len())
+	for _, key := range []int{3, 4, 5} {
+		_, ok := lt.Peek(key)
+		assert.False(t, ok, "key %d should have been evicted", key)
+	}
+	for _, key := range []int{1, 2, 6, 100, 101, 102} {
+		_, ok := lt.Peek(key)
+		assert.True(t, ok, "key %d should have survived", key)
+	}
+	assert.Equal(t, []int{102, 101, 100, 2, 1}, lt.Keys()[:5])
+}
+
+func TestLRUHashTable(t *testing.T) {
+	lt := elastichash.NewLRUHashTable[string, int](10, 0.1)
+	replaced, err := lt.Insert("a", 1)
+	require.NoError(t, err)
+	assert.False(t, replaced)
+	_, err = lt.Insert("b", 2)
+	require.NoError(t, err)
+
+	replaced, err = lt.Insert("a", 3)
+	require.NoError(t, err)
+	assert.True(t, replaced)
+	v, ok := lt.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, []string{"a", "b"}, lt.Keys())
+
+	_, ok = lt.Peek("b")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a", "b"}, lt.Keys(), "Peek should not change recency")
+
+	assert.True(t, lt.Delete("a"))
+	assert.False(t, lt.Delete("a"))
+	assert.Equal(t, []string{"b"}, lt.Keys())
+	assert.Equal(t, 1, lt.Len())
+	assert.Equal(t, 10, lt.Cap())
+}
@@ -0,0 +1,371 @@
+package elastichash
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// MarshalMsgpack encodes the table as a MessagePack map with "capacity",
+// "delta" and "entries" fields, where entries maps each live key to its
+// value. Integers take the smallest MessagePack form that holds them, which
+// makes int-keyed tables far more compact than JSON. Keys and values must be
+// booleans, numbers or strings, and the capacity is limited as for
+// MarshalJSON. The encoder is hand-rolled so the package needs no MessagePack
+// dependency.
+func (ht *HashTable[K, V]) MarshalMsgpack() ([]byte, error) {
+	if err := ht.checkEncodable(); err != nil {
+		return nil, err
+	}
+	b := appendMsgpackMapLen(nil, 3)
+	b = appendMsgpackString(b, "capacity")
+	b = appendMsgpackUint(b, uint64(ht.capacity))
+	b = appendMsgpackString(b, "delta")
+	b = binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(ht.delta))
+	b = appendMsgpackString(b, "entries")
+	b = appendMsgpackMapLen(b, ht.Len())
+	var err error
+	ht.Range(func(key K, value V) bool {
+		if b, err = appendMsgpack(b, reflect.ValueOf(key)); err != nil {
+			return false
+		}
+		b, err = appendMsgpack(b, reflect.ValueOf(value))
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// UnmarshalMsgpack rebuilds the table from MarshalMsgpack output, following
+// the same rules as UnmarshalJSON. Any integer form is accepted for an
+// integer field as long as the value fits.
+func (ht *HashTable[K, V]) UnmarshalMsgpack(data []byte) error {
+	r := &msgpackReader{b: data}
+	fields, err := r.readMapLen()
+	if err != nil {
+		return err
+	}
+	var x Exported[K, V]
+	for range fields {
+		name, err := r.readString()
+		if err != nil {
+			return err
+		}
+		switch name {
+		case "capacity":
+			u, neg, err := r.readInteger()
+			if err != nil {
+				return err
+			}
+			if neg || u > uint64(maxSerializedCapacity) {
+				return fmt.Errorf("elastichash: msgpack capacity out of range: %w", InvalidFormatErr)
+			}
+			x.Capacity = int(u)
+		case "delta":
+			if x.Delta, err = r.readFloat(); err != nil {
+				return err
+			}
+		case "entries":
+			n, err := r.readMapLen()
+			if err != nil {
+				return err
+			}
+			// Every entry takes at least two bytes, which bounds the
+			// allocation for corrupt lengths.
+			if n > len(r.b)/2 {
+				return fmt.Errorf("elastichash: msgpack map of %d entries in %d bytes: %w", n, len(r.b), InvalidFormatErr)
+			}
+			x.Entries = make([]Entry[K, V], n)
+			for i := range x.Entries {
+				e := &x.Entries[i]
+				if err := r.decode(reflect.ValueOf(&e.Key).Elem()); err != nil {
+					return err
+				}
+				if err := r.decode(reflect.ValueOf(&e.Value).Elem()); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("elastichash: unknown msgpack field %q: %w", name, InvalidFormatErr)
+		}
+	}
+	if len(r.b) > 0 {
+		return fmt.Errorf("elastichash: %d trailing msgpack bytes: %w", len(r.b), InvalidFormatErr)
+	}
+	return ht.Import(x)
+}
+
+// appendMsgpack appends the MessagePack encoding of a boolean, number or
+// string.
+func appendMsgpack(b []byte, v reflect.Value) ([]byte, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, 0xc3), nil
+		}
+		return append(b, 0xc2), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendMsgpackInt(b, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendMsgpackUint(b, v.Uint()), nil
+	case reflect.Float32:
+		return binary.BigEndian.AppendUint32(append(b, 0xca), math.Float32bits(float32(v.Float()))), nil
+	case reflect.Float64:
+		return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(v.Float())), nil
+	case reflect.String:
+		return appendMsgpackString(b, v.String()), nil
+	}
+	return nil, fmt.Errorf("elastichash: cannot msgpack-encode %s", v.Type())
+}
+
+func appendMsgpackInt(b []byte, n int64) []byte {
+	switch {
+	case n >= 0:
+		return appendMsgpackUint(b, uint64(n))
+	case n >= -32:
+		return append(b, byte(n))
+	case n >= math.MinInt8:
+		return append(b, 0xd0, byte(n))
+	case n >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(n))
+	case n >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(n))
+}
+
+func appendMsgpackUint(b []byte, n uint64) []byte {
+	switch {
+	case n < 0x80:
+		return append(b, byte(n))
+	case n <= math.MaxUint8:
+		return append(b, 0xcc, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xcf), n)
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+func appendMsgpackMapLen(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xde), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, 0xdf), uint32(n))
+}
+
+// msgpackReader consumes MessagePack values from the front of b.
+type msgpackReader struct {
+	b []byte
+}
+
+func (r *msgpackReader) next(n int) ([]byte, error) {
+	if n > len(r.b) {
+		return nil, fmt.Errorf("elastichash: reading msgpack: %w", io.ErrUnexpectedEOF)
+	}
+	p := r.b[:n]
+	r.b = r.b[n:]
+	return p, nil
+}
+
+func (r *msgpackReader) readByte() (byte, error) {
+	p, err := r.next(1)
+	if err != nil {
+		return 0, err
+	}
+	return p[0], nil
+}
+
+// readUint reads an n-byte big-endian unsigned integer.
+func (r *msgpackReader) readUint(n int) (uint64, error) {
+	p, err := r.next(n)
+	if err != nil {
+		return 0, err
+	}
+	var u uint64
+	for _, c := range p {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+func unexpectedMsgpack(c byte, want string) error {
+	return fmt.Errorf("elastichash: msgpack type byte %#x is not %s: %w", c, want, InvalidFormatErr)
+}
+
+// readInteger reads any MessagePack integer. A negative value is returned as
+// its two's complement with neg set.
+func (r *msgpackReader) readInteger() (u uint64, neg bool, err error) {
+	c, err := r.readByte()
+	if err != nil {
+		return 0, false, err
+	}
+	signed := func(n int, bits uint) (uint64, bool, error) {
+		u, err := r.readUint(n)
+		// Shift the value's sign bit to the top to sign-extend it.
+		s := int64(u<<(64-bits)) >> (64 - bits)
+		return uint64(s), s < 0, err
+	}
+	switch {
+	case c < 0x80:
+		return uint64(c), false, nil
+	case c >= 0xe0:
+		return uint64(int64(int8(c))), true, nil
+	case c == 0xcc:
+		u, err = r.readUint(1)
+	case c == 0xcd:
+		u, err = r.readUint(2)
+	case c == 0xce:
+		u, err = r.readUint(4)
+	case c == 0xcf:
+		u, err = r.readUint(8)
+	case c == 0xd0:
+		return signed(1, 8)
+	case c == 0xd1:
+		return signed(2, 16)
+	case c == 0xd2:
+		return signed(4, 32)
+	case c == 0xd3:
+		return signed(8, 64)
+	default:
+		return 0, false, unexpectedMsgpack(c, "an integer")
+	}
+	return u, false, err
+}
+
+func (r *msgpackReader) readFloat() (float64, error) {
+	c, err := r.readByte()
+	if err != nil {
+		return 0, err
+	}
+	switch c {
+	case 0xca:
+		u, err := r.readUint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := r.readUint(8)
+		return math.Float64frombits(u), err
+	}
+	return 0, unexpectedMsgpack(c, "a float")
+}
+
+func (r *msgpackReader) readString() (string, error) {
+	c, err := r.readByte()
+	if err != nil {
+		return "", err
+	}
+	var n uint64
+	switch {
+	case c&0xe0 == 0xa0:
+		n = uint64(c & 0x1f)
+	case c == 0xd9:
+		n, err = r.readUint(1)
+	case c == 0xda:
+		n, err = r.readUint(2)
+	case c == 0xdb:
+		n, err = r.readUint(4)
+	default:
+		return "", unexpectedMsgpack(c, "a string")
+	}
+	if err != nil {
+		return "", err
+	}
+	if n > uint64(len(r.b)) {
+		return "", fmt.Errorf("elastichash: reading msgpack: %w", io.ErrUnexpectedEOF)
+	}
+	p, err := r.next(int(n))
+	return string(p), err
+}
+
+func (r *msgpackReader) readMapLen() (int, error) {
+	c, err := r.readByte()
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	switch {
+	case c&0xf0 == 0x80:
+		return int(c & 0x0f), nil
+	case c == 0xde:
+		n, err = r.readUint(2)
+	case c == 0xdf:
+		n, err = r.readUint(4)
+	default:
+		return 0, unexpectedMsgpack(c, "a map")
+	}
+	return int(n), err
+}
+
+// decode reads a value into v, which must be a settable boolean, number or
+// string.
+func (r *msgpackReader) decode(v reflect.Value) error {
+	overflow := func() error {
+		return fmt.Errorf("elastichash: msgpack value overflows %s: %w", v.Type(), InvalidFormatErr)
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		c, err := r.readByte()
+		if err != nil {
+			return err
+		}
+		if c != 0xc2 && c != 0xc3 {
+			return unexpectedMsgpack(c, "a boolean")
+		}
+		v.SetBool(c == 0xc3)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		u, neg, err := r.readInteger()
+		if err != nil {
+			return err
+		}
+		if !neg && u > math.MaxInt64 || v.OverflowInt(int64(u)) {
+			return overflow()
+		}
+		v.SetInt(int64(u))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, neg, err := r.readInteger()
+		if err != nil {
+			return err
+		}
+		if neg || v.OverflowUint(u) {
+			return overflow()
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := r.readFloat()
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.String:
+		s, err := r.readString()
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+	default:
+		return fmt.Errorf("elastichash: cannot msgpack-decode %s", v.Type())
+	}
+	return nil
+}
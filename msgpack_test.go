@@ -0,0 +1,133 @@
+package elastichash_test
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestMsgpackRoundTripIntKeys(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](2000, 0.1)
+	keys := []int{0, 1, 127, 128, 255, 256, 65535, 65536, math.MaxInt32 + 1, math.MaxInt64,
+		-1, -32, -33, -128, -129, -32768, -32769, math.MinInt32, math.MinInt32 - 1, math.MinInt64}
+	for i, k := range keys {
+		mustInsert(t, ht, k, -i)
+	}
+	for i := range 1000 {
+		mustInsert(t, ht, i*1000, i)
+	}
+	data, err := ht.MarshalMsgpack()
+	require.NoError(t, err)
+
+	var loaded elastichash.HashTable[int, int]
+	require.NoError(t, loaded.UnmarshalMsgpack(data))
+	assert.Equal(t, 2000, loaded.Cap())
+	assert.Equal(t, 0.1, loaded.Delta())
+	assert.True(t, ht.Equal(&loaded, nil))
+
+	asJSON, err := json.Marshal(ht)
+	require.NoError(t, err)
+	assert.Less(t, len(data), len(asJSON)/2, "msgpack should be much smaller than JSON for int keys")
+}
+
+func TestMsgpackRoundTripOtherTypes(t *testing.T) {
+	strs := elastichash.NewHashTable[string, string](100, 0.2)
+	mustInsert(t, strs, "", "empty key")
+	mustInsert(t, strs, "short", string(make([]byte, 40)))
+	mustInsert(t, strs, string(make([]byte, 300)), string(make([]byte, 70000)))
+	data, err := strs.MarshalMsgpack()
+	require.NoError(t, err)
+	loadedStrs := elastichash.NewHashTable[string, string](1, 0.5)
+	require.NoError(t, loadedStrs.UnmarshalMsgpack(data))
+	assert.True(t, strs.Equal(loadedStrs, nil))
+
+	type id uint8
+	flags := elastichash.NewHashTable[id, bool](20, 0.1)
+	mustInsert(t, flags, 3, true)
+	mustInsert(t, flags, 200, false)
+	data, err = flags.MarshalMsgpack()
+	require.NoError(t, err)
+	var loadedFlags elastichash.HashTable[id, bool]
+	require.NoError(t, loadedFlags.UnmarshalMsgpack(data))
+	assert.True(t, flags.Equal(&loadedFlags, nil))
+
+	floats := elastichash.NewHashTable[float32, float64](20, 0.1)
+	mustInsert(t, floats, 1.5, math.Pi)
+	mustInsert(t, floats, -2, math.Inf(-1))
+	data, err = floats.MarshalMsgpack()
+	require.NoError(t, err)
+	var loadedFloats elastichash.HashTable[float32, float64]
+	require.NoError(t, loadedFloats.UnmarshalMsgpack(data))
+	assert.True(t, floats.Equal(&loadedFloats, nil))
+
+	slices := elastichash.NewHashTable[int, []int](10, 0.1)
+	mustInsert(t, slices, 1, []int{1})
+	_, err = slices.MarshalMsgpack()
+	assert.ErrorContains(t, err, "cannot msgpack-encode")
+}
+
+func TestMsgpackWireFormat(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](2, 0.5)
+	mustInsert(t, ht, 1, -1)
+	data, err := ht.MarshalMsgpack()
+	require.NoError(t, err)
+
+	want := []byte{0x83}
+	want = append(want, 0xa8)
+	want = append(want, "capacity"...)
+	want = append(want, 0x02, 0xa5)
+	want = append(want, "delta"...)
+	want = append(want, 0xcb, 0x3f, 0xe0, 0, 0, 0, 0, 0, 0, 0xa7)
+	want = append(want, "entries"...)
+	want = append(want, 0x81, 0x01, 0xff)
+	assert.Equal(t, want, data)
+}
+
+func TestMsgpackUnmarshalErrors(t *testing.T) {
+	ht := elastichash.NewHashTable[int8, int](100, 0.1)
+	for i := range 50 {
+		mustInsert(t, ht, int8(i), i)
+	}
+	data, err := ht.MarshalMsgpack()
+	require.NoError(t, err)
+
+	var loaded elastichash.HashTable[int8, int]
+	for n := range len(data) {
+		err := loaded.UnmarshalMsgpack(data[:n])
+		require.Error(t, err, "truncated at %d bytes", n)
+	}
+	assert.ErrorIs(t, loaded.UnmarshalMsgpack(data[:5]), io.ErrUnexpectedEOF)
+	assert.ErrorIs(t, loaded.UnmarshalMsgpack(append(data, 0)), elastichash.InvalidFormatErr)
+
+	wide := elastichash.NewHashTable[int, int](10, 0.1)
+	mustInsert(t, wide, 300, 1)
+	data, err = wide.MarshalMsgpack()
+	require.NoError(t, err)
+	assert.ErrorIs(t, loaded.UnmarshalMsgpack(data), elastichash.InvalidFormatErr, "300 overflows int8")
+
+	unknown := []byte{0x81, 0xa3, 'f', 'o', 'o', 0x01}
+	assert.ErrorContains(t, loaded.UnmarshalMsgpack(unknown), `unknown msgpack field "foo"`)
+	assert.ErrorIs(t, loaded.UnmarshalMsgpack([]byte{0x91}), elastichash.InvalidFormatErr, "an array is not a table")
+
+	tooMany := elastichash.NewHashTable[int, int](100, 0.1)
+	for i := range 10 {
+		mustInsert(t, tooMany, i, i)
+	}
+	data, err = tooMany.MarshalMsgpack()
+	require.NoError(t, err)
+	data[len("\x83\xa8capacity")] = 2
+	var small elastichash.HashTable[int, int]
+	assert.ErrorIs(t, small.UnmarshalMsgpack(data), elastichash.OutOfSpaceErr)
+	assert.Zero(t, small.Len())
+
+	// A capacity of 1<<31-1 as a uint32 with delta 0.5 and no entries.
+	huge := []byte("\x83\xa8capacity\xce\x7f\xff\xff\xff\xa5delta\xcb\x3f\xe0\x00\x00\x00\x00\x00\x00\xa7entries\x80")
+	assert.ErrorIs(t, small.UnmarshalMsgpack(huge), elastichash.InvalidFormatErr)
+	assert.Zero(t, small.Cap())
+}
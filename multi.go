@@ -0,0 +1,72 @@
+package elastichash
+
+import "slices"
+
+// MultiHashTable maps each key to a list of values. Insert appends rather
+// than replaces, and each distinct key takes up one slot of the underlying
+// HashTable regardless of how many values it holds.
+type MultiHashTable[K comparable, V comparable] struct {
+	ht *HashTable[K, []V]
+}
+
+func NewMultiHashTable[K ValidKey, V comparable](capacity int, delta float64, opts ...Option) *MultiHashTable[K, V] {
+	return &MultiHashTable[K, V]{ht: NewHashTable[K, []V](capacity, delta, opts...)}
+}
+
+// Insert appends value to the values stored under key. It only fails when
+// key is new and the table has no room for it.
+func (mt *MultiHashTable[K, V]) Insert(key K, value V) error {
+	if i, idx, ok := mt.ht.find(key); ok {
+		e := &mt.ht.levels[i][idx]
+		e.value = append(e.value, value)
+		return nil
+	}
+	_, err := mt.ht.Insert(key, []V{value})
+	return err
+}
+
+// Get returns a copy of the values stored under key in insertion order.
+func (mt *MultiHashTable[K, V]) Get(key K) ([]V, bool) {
+	values, ok := mt.ht.Get(key)
+	return slices.Clone(values), ok
+}
+
+// GetAll is Get without the presence flag; a missing key yields nil.
+func (mt *MultiHashTable[K, V]) GetAll(key K) []V {
+	values, _ := mt.Get(key)
+	return values
+}
+
+// Delete removes key along with all of its values.
+func (mt *MultiHashTable[K, V]) Delete(key K) bool {
+	return mt.ht.Delete(key)
+}
+
+// DeleteValue removes the first occurrence of value under key and reports
+// whether there was one. A key whose last value is removed is deleted.
+func (mt *MultiHashTable[K, V]) DeleteValue(key K, value V) bool {
+	i, idx, ok := mt.ht.find(key)
+	if !ok {
+		return false
+	}
+	e := &mt.ht.levels[i][idx]
+	at := slices.Index(e.value, value)
+	if at < 0 {
+		return false
+	}
+	e.value = slices.Delete(e.value, at, at+1)
+	if len(e.value) == 0 {
+		mt.ht.Delete(key)
+	}
+	return true
+}
+
+// Len returns the number of distinct keys.
+func (mt *MultiHashTable[K, V]) Len() int {
+	return mt.ht.Len()
+}
+
+// Cap returns the configured capacity, counted in distinct keys.
+func (mt *MultiHashTable[K, V]) Cap() int {
+	return mt.ht.Cap()
+}
@@ -0,0 +1,51 @@
+package elastichash_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestMultiHashTable(t *testing.T) {
+	mt := elastichash.NewMultiHashTable[string, int](10, 0.1)
+	require.NoError(t, mt.Insert("a", 1))
+	require.NoError(t, mt.Insert("a", 2))
+	require.NoError(t, mt.Insert("a", 1))
+	require.NoError(t, mt.Insert("b", 3))
+	assert.Equal(t, 2, mt.Len())
+
+	values, ok := mt.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, []int{1, 2, 1}, values)
+	values[0] = 100
+	assert.Equal(t, []int{1, 2, 1}, mt.GetAll("a"), "Get returns a copy")
+	assert.Nil(t, mt.GetAll("missing"))
+
+	assert.True(t, mt.DeleteValue("a", 1))
+	assert.Equal(t, []int{2, 1}, mt.GetAll("a"), "only the first match is removed")
+	assert.False(t, mt.DeleteValue("a", 5))
+	assert.False(t, mt.DeleteValue("missing", 1))
+
+	assert.True(t, mt.DeleteValue("b", 3))
+	_, ok = mt.Get("b")
+	assert.False(t, ok, "removing the last value removes the key")
+	assert.Equal(t, 1, mt.Len())
+
+	assert.True(t, mt.Delete("a"))
+	assert.Zero(t, mt.Len())
+}
+
+func TestMultiHashTableFull(t *testing.T) {
+	mt := elastichash.NewMultiHashTable[int, int](10, 0.1)
+	for i := range 9 {
+		require.NoError(t, mt.Insert(i, i))
+	}
+	assert.ErrorIs(t, mt.Insert(9, 9), elastichash.OutOfSpaceErr)
+	for range 100 {
+		require.NoError(t, mt.Insert(0, 0), "existing keys take more values")
+	}
+	assert.Len(t, mt.GetAll(0), 101)
+}
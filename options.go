@@ -0,0 +1,151 @@
+package elastichash
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+)
+
+// Option configures optional HashTable behavior at construction time.
+type Option func(*options)
+
+type options struct {
+	autoGrow  bool
+	probe     ProbeFunc
+	c         float64
+	threshold float64
+	observer  Observer
+	bloom     bool
+	// normalizer holds a func(K) K; Option is not generic, so the
+	// constructor checks its type.
+	normalizer any
+
+	// err records the first invalid option so the constructor can report it.
+	err error
+}
+
+// WithAutoGrow makes Insert double the table's capacity and rehash every live
+// entry instead of failing when the table runs out of room. Without it the
+// table keeps the fixed capacity it was created with.
+func WithAutoGrow() Option {
+	return func(o *options) {
+		o.autoGrow = true
+	}
+}
+
+// WithProbeFunc sets the probe sequence Insert and Get walk on every level.
+// The default is QuadraticProbe.
+func WithProbeFunc(p ProbeFunc) Option {
+	return func(o *options) {
+		o.probe = p
+	}
+}
+
+// WithC sets the constant that scales each level's probe limit; the default
+// is 4. A smaller c spends fewer probes per operation but gives up on a level
+// sooner, so inserts fail more often as the table fills; a larger c trades
+// CPU for a higher fill rate. c must be positive.
+func WithC(c float64) Option {
+	return func(o *options) {
+		if !(c > 0) || math.IsInf(c, 1) {
+			o.setErr(fmt.Errorf("elastichash: c must be positive and finite, got %v", c))
+			return
+		}
+		o.c = c
+	}
+}
+
+// WithThreshold sets the free fraction of the next level below which Insert
+// commits to the current level rather than saving the current level's slots
+// for later; the default is 0.25. It must lie strictly between 0 and 1.
+func WithThreshold(threshold float64) Option {
+	return func(o *options) {
+		if !(threshold > 0 && threshold < 1) {
+			o.setErr(fmt.Errorf("elastichash: threshold must be in (0, 1), got %v", threshold))
+			return
+		}
+		o.threshold = threshold
+	}
+}
+
+// Observer receives a callback from every successful Insert and every Get,
+// which is enough to feed counters and probe-length histograms in a metrics
+// library without this package depending on one. Callbacks run on the
+// caller's goroutine and should return quickly.
+type Observer interface {
+	// OnInsert reports the slots inspected and the level now holding the
+	// key, including Inserts that replaced an existing value.
+	OnInsert(probes int, level int)
+	// OnGet reports whether the key was found and the slots inspected.
+	OnGet(hit bool, probes int)
+}
+
+// WithObserver installs an Observer on the table's Insert and Get paths.
+func WithObserver(obs Observer) Option {
+	return func(o *options) {
+		o.observer = obs
+	}
+}
+
+// WithBloomFilter keeps a Bloom filter of inserted keys in front of the
+// levels, so lookups for most absent keys return without probing at the cost
+// of about 10 bits per slot. Deleted keys stay in the filter until the table
+// next compacts or resizes, so a delete-heavy table rejects fewer misses.
+func WithBloomFilter() Option {
+	return func(o *options) {
+		o.bloom = true
+	}
+}
+
+// WithKeyNormalizer maps every key through normalize before it is hashed,
+// compared or stored, so keys with the same normal form are the same key; a
+// normalizer of strings.ToLower makes string keys case-insensitive. Stored
+// keys, as seen by Range and Keys, are the normalized ones. normalize must be
+// a func of the table's key type or the constructor rejects it.
+func WithKeyNormalizer[K comparable](normalize func(K) K) Option {
+	return func(o *options) {
+		o.normalizer = normalize
+	}
+}
+
+// Options gathers the constructor arguments and the most common Option
+// settings into one struct for NewHashTableWithOptions. Every zero field
+// means its default, so Options{} gives the same table as NewHashTable with a
+// delta of 0.1 and no options.
+type Options[K comparable] struct {
+	// Delta is the fraction of slots kept free; 0 means 0.1.
+	Delta float64
+	// C scales each level's probe limit, as WithC; 0 means 4.
+	C float64
+	// Threshold is WithThreshold's setting; 0 means 0.25.
+	Threshold float64
+	// Hasher hashes keys, as NewHashTableWithHasher's argument; nil means
+	// HashKey with Seed.
+	Hasher func(K) uint64
+	// Seed seeds HashKey, as NewHashTableWithSeed's argument; the zero Seed
+	// means the seed NewHashTable shares across tables.
+	Seed maphash.Seed
+	// AutoGrow is WithAutoGrow.
+	AutoGrow bool
+}
+
+// options translates the non-zero fields that correspond to an Option.
+func (o Options[K]) options() []Option {
+	var opts []Option
+	if o.C != 0 {
+		opts = append(opts, WithC(o.C))
+	}
+	if o.Threshold != 0 {
+		opts = append(opts, WithThreshold(o.Threshold))
+	}
+	if o.AutoGrow {
+		opts = append(opts, WithAutoGrow())
+	}
+	return opts
+}
+
+func (o *options) setErr(err error) {
+	if o.err == nil {
+		o.err = err
+	}
+}
@@ -0,0 +1,219 @@
+package elastichash_test
+
+import (
+	"hash/maphash"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestWithC(t *testing.T) {
+	// An identity hasher makes the layout deterministic: a capacity of 16
+	// gives levels of 1, 1, 3 and 11 slots, and keys 0, 1 and 2 take one slot
+	// on each of the first three. Key 5 then collides with key 2 on level 2,
+	// where only its second probe finds a free slot.
+	identity := func(k int) uint64 { return uint64(k) }
+	occupancies := func(opts ...elastichash.Option) []int {
+		ht := elastichash.NewHashTableWithHasher[int, int](16, 0.1, identity, opts...)
+		for k := range 3 {
+			mustInsert(t, ht, k, k)
+		}
+		mustInsert(t, ht, 5, 5)
+		var out []int
+		for _, l := range ht.Stats().Levels {
+			out = append(out, l.Occupancy)
+		}
+		return out
+	}
+	assert.Equal(t, []int{1, 1, 2, 0}, occupancies(), "the default c probes level 2 deep enough")
+	assert.Equal(t, []int{1, 1, 1, 1}, occupancies(elastichash.WithC(1)), "c=1 gives up on level 2 after one probe")
+	assert.Equal(t, 32.0, elastichash.NewHashTable[int, int](10, 0.1, elastichash.WithC(32)).Stats().C)
+}
+
+func TestWithCRejectsInvalidValues(t *testing.T) {
+	for _, c := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		assert.Panics(t, func() {
+			elastichash.NewHashTable[int, int](10, 0.1, elastichash.WithC(c))
+		})
+	}
+}
+
+func TestWithThreshold(t *testing.T) {
+	occupancies := func(threshold float64) []int {
+		ht := elastichash.NewHashTable[int, int](1000, 0.1, elastichash.WithC(1), elastichash.WithThreshold(threshold))
+		assert.Equal(t, threshold, ht.Stats().Threshold)
+		for i := range 200 {
+			mustInsert(t, ht, i, i)
+		}
+		var out []int
+		for _, l := range ht.Stats().Levels {
+			out = append(out, l.Occupancy)
+		}
+		return out
+	}
+	low, high := occupancies(0.05), occupancies(0.95)
+	assert.NotEqual(t, low, high, "the threshold should change how inserts spread over levels")
+
+	for _, threshold := range []float64{0, 1, -0.5, math.NaN()} {
+		assert.Panics(t, func() {
+			elastichash.NewHashTable[int, int](10, 0.1, elastichash.WithThreshold(threshold))
+		})
+	}
+}
+
+type recordingObserver struct {
+	inserts [][2]int
+	gets    []int
+	hits    int
+}
+
+func (o *recordingObserver) OnInsert(probes, level int) {
+	o.inserts = append(o.inserts, [2]int{probes, level})
+}
+
+func (o *recordingObserver) OnGet(hit bool, probes int) {
+	o.gets = append(o.gets, probes)
+	if hit {
+		o.hits++
+	}
+}
+
+func TestWithObserver(t *testing.T) {
+	obs := &recordingObserver{}
+	ht := elastichash.NewHashTable[int, int](100, 0.1, elastichash.WithObserver(obs))
+	for i := range 20 {
+		_, probes, err := ht.InsertWithProbes(i, i)
+		require.NoError(t, err)
+		require.Len(t, obs.inserts, i+1)
+		assert.Equal(t, probes, obs.inserts[i][0])
+		assert.GreaterOrEqual(t, obs.inserts[i][1], 0)
+		assert.Less(t, obs.inserts[i][1], len(ht.Stats().Levels))
+	}
+
+	_, _, hitProbes := ht.GetWithProbes(3)
+	_, ok, missProbes := ht.GetWithProbes(-1)
+	require.False(t, ok)
+	ht.Get(4)
+	require.Len(t, obs.gets, 3)
+	assert.Equal(t, hitProbes, obs.gets[0])
+	assert.Equal(t, missProbes, obs.gets[1])
+	assert.Equal(t, 2, obs.hits)
+
+	full := elastichash.NewHashTable[int, int](10, 0.1, elastichash.WithObserver(obs))
+	for i := range full.MaxLen() {
+		mustInsert(t, full, i, i)
+	}
+	before := len(obs.inserts)
+	_, err := full.Insert(-1, -1)
+	require.Error(t, err)
+	assert.Len(t, obs.inserts, before, "failed inserts are not reported")
+}
+
+func TestWithKeyNormalizer(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1, elastichash.WithKeyNormalizer(strings.ToLower))
+	mustInsert(t, ht, "Foo", 1)
+	v, ok := ht.Get("foo")
+	require.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.True(t, ht.Contains("FOO"))
+
+	replaced, err := ht.Insert("fOO", 2)
+	require.NoError(t, err)
+	assert.True(t, replaced)
+	assert.Equal(t, 1, ht.Len())
+	assert.Equal(t, []string{"foo"}, ht.Keys(), "keys are stored normalized")
+
+	require.True(t, ht.Update("FoO", 3))
+	assert.Equal(t, 3, ht.MustGet("foo"))
+	require.NoError(t, ht.Resize(20))
+	assert.Equal(t, 3, ht.MustGet("FOO"), "the normalizer survives a rebuild")
+	assert.True(t, ht.Delete("FOO"))
+	assert.Zero(t, ht.Len())
+
+	st := elastichash.NewShardedHashTable[string, int](4, 100, 0.1, elastichash.WithKeyNormalizer(strings.ToLower))
+	for _, k := range []string{"Alpha", "Beta", "Gamma"} {
+		_, err := st.Insert(k, len(k))
+		require.NoError(t, err)
+	}
+	v, ok = st.Get("GAMMA")
+	require.True(t, ok, "every shard sees the same normalized key")
+	assert.Equal(t, 5, v)
+
+	_, err = elastichash.TryNewHashTable[string, int](10, 0.1, elastichash.WithKeyNormalizer(func(k int) int { return k }))
+	assert.ErrorContains(t, err, "key normalizer")
+}
+
+func TestNewHashTableWithOptions(t *testing.T) {
+	type point struct{ x, y int }
+	hashes := 0
+	ht := elastichash.NewHashTableWithOptions[point, string](8, elastichash.Options[point]{
+		Delta:     0.2,
+		C:         2,
+		Threshold: 0.5,
+		Hasher: func(p point) uint64 {
+			hashes++
+			return uint64(p.x)*31 + uint64(p.y)
+		},
+		Seed:     maphash.MakeSeed(),
+		AutoGrow: true,
+	}, elastichash.WithBloomFilter())
+	for i := range 50 {
+		mustInsert(t, ht, point{i, -i}, "p")
+	}
+	assert.Equal(t, 50, ht.Len())
+	assert.Greater(t, ht.Cap(), 8, "AutoGrow should have grown the table")
+	assert.True(t, ht.Contains(point{7, -7}))
+	assert.Positive(t, hashes)
+
+	s := ht.Stats()
+	assert.Equal(t, 0.2, s.Delta)
+	assert.Equal(t, 2.0, s.C)
+	assert.Equal(t, 0.5, s.Threshold)
+
+	assert.Panics(t, func() {
+		elastichash.NewHashTableWithOptions[point, string](8, elastichash.Options[point]{})
+	}, "a struct key needs a Hasher")
+	assert.Panics(t, func() {
+		elastichash.NewHashTableWithOptions[int, int](8, elastichash.Options[int]{C: -1})
+	})
+}
+
+func TestNewHashTableWithOptionsDefaults(t *testing.T) {
+	ht := elastichash.NewHashTableWithOptions[string, int](100, elastichash.Options[string]{})
+	s := ht.Stats()
+	assert.Equal(t, 0.1, s.Delta)
+	assert.Equal(t, 4.0, s.C)
+	assert.Equal(t, 0.25, s.Threshold)
+
+	for i := range ht.MaxLen() {
+		mustInsert(t, ht, strings.Repeat("k", i+1), i)
+	}
+	_, err := ht.Insert("one too many", 0)
+	assert.ErrorIs(t, err, elastichash.OutOfSpaceErr, "tables do not grow by default")
+
+	plain := elastichash.NewHashTable[string, int](100, 0.1)
+	for i := range ht.MaxLen() {
+		mustInsert(t, plain, strings.Repeat("k", i+1), i)
+	}
+	assert.Equal(t, plain.GoString(), ht.GoString(), "the default seed should match NewHashTable's")
+}
+
+func TestConfigurationGetters(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.15, elastichash.WithC(2.5), elastichash.WithThreshold(0.4))
+	assert.Equal(t, 0.15, ht.Delta())
+	assert.Equal(t, 2.5, ht.C())
+	assert.Equal(t, 0.4, ht.Threshold())
+
+	defaults := elastichash.NewHashTable[string, int](100, 0.2)
+	assert.Equal(t, 0.2, defaults.Delta())
+	assert.Equal(t, 4.0, defaults.C())
+	assert.Equal(t, 0.25, defaults.Threshold())
+
+	require.NoError(t, ht.Resize(200))
+	assert.Equal(t, 2.5, ht.C(), "configuration survives a rebuild")
+}
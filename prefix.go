@@ -0,0 +1,16 @@
+package elastichash
+
+import "strings"
+
+// ScanPrefix returns every entry of ht whose key starts with prefix, in
+// unspecified order. The table is unordered, so this visits every entry.
+func ScanPrefix[K ~string, V any](ht *HashTable[K, V], prefix K) []Entry[K, V] {
+	var out []Entry[K, V]
+	ht.Range(func(key K, value V) bool {
+		if strings.HasPrefix(string(key), string(prefix)) {
+			out = append(out, Entry[K, V]{Key: key, Value: value})
+		}
+		return true
+	})
+	return out
+}
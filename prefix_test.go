@@ -0,0 +1,29 @@
+package elastichash_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestScanPrefix(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](10, 0.1)
+	mustInsert(t, ht, "a/b", 1)
+	mustInsert(t, ht, "a/c", 2)
+	mustInsert(t, ht, "x/y", 3)
+	mustInsert(t, ht, "ab", 4)
+
+	assert.ElementsMatch(t, []elastichash.Entry[string, int]{
+		{Key: "a/b", Value: 1},
+		{Key: "a/c", Value: 2},
+	}, elastichash.ScanPrefix(ht, "a/"))
+	assert.Len(t, elastichash.ScanPrefix(ht, ""), 4)
+	assert.Empty(t, elastichash.ScanPrefix(ht, "z/"))
+
+	type path string
+	paths := elastichash.NewHashTable[path, int](10, 0.1)
+	mustInsert(t, paths, path("etc/hosts"), 1)
+	assert.Len(t, elastichash.ScanPrefix(paths, "etc/"), 1)
+}
@@ -0,0 +1,26 @@
+package elastichash
+
+// ProbeFunc maps the j-th probe of a key with the given hash to a slot in
+// [0, size). Insert and Get share the table's ProbeFunc, so a key is always
+// looked for along the same sequence it was placed on.
+type ProbeFunc func(hash uint64, j int64, size int) int
+
+// LinearProbe steps one slot at a time from the key's home slot.
+func LinearProbe(hash uint64, j int64, size int) int {
+	return int((hash + uint64(j)) % uint64(size))
+}
+
+// QuadraticProbe offsets the home slot by j*j. It is the default. The high
+// half of the hash is folded into the low half before reducing, so entropy in
+// either half spreads keys even when size is a power of two.
+func QuadraticProbe(hash uint64, j int64, size int) int {
+	folded := hash ^ hash>>32
+	return int((folded + uint64(j)*uint64(j)) % uint64(size))
+}
+
+// DoubleHashProbe steps from the home slot by a stride derived from the high
+// half of the hash, so keys sharing a home slot follow different sequences.
+func DoubleHashProbe(hash uint64, j int64, size int) int {
+	stride := 1 + (hash>>32)%uint64(max(size-1, 1))
+	return int((hash + uint64(j)*stride) % uint64(size))
+}
@@ -0,0 +1,69 @@
+package elastichash_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestProbeFuncs(t *testing.T) {
+	probes := map[string]elastichash.ProbeFunc{
+		"linear":    elastichash.LinearProbe,
+		"quadratic": elastichash.QuadraticProbe,
+		"double":    elastichash.DoubleHashProbe,
+	}
+	for name, p := range probes {
+		t.Run(name, func(t *testing.T) {
+			ht := elastichash.NewHashTable[int, int](12000, 0.1, elastichash.WithProbeFunc(p))
+			for i := range 10000 {
+				mustInsert(t, ht, i, i)
+			}
+			for i := range 10000 {
+				v, ok := ht.Get(i)
+				require.True(t, ok, "key %d should be retrievable", i)
+				assert.Equal(t, i, v)
+			}
+			assert.False(t, ht.Contains(10000))
+		})
+	}
+}
+
+func TestProbeFuncsStayInRange(t *testing.T) {
+	for _, p := range []elastichash.ProbeFunc{elastichash.LinearProbe, elastichash.QuadraticProbe, elastichash.DoubleHashProbe} {
+		for _, size := range []int{1, 2, 7, 64} {
+			for j := range int64(100) {
+				idx := p(^uint64(0)-uint64(j), j, size)
+				assert.GreaterOrEqual(t, idx, 0)
+				assert.Less(t, idx, size)
+			}
+		}
+	}
+}
+
+func TestQuadraticProbeUsesFullHash(t *testing.T) {
+	// The old probe masked the hash to its low 32 bits before reducing.
+	masked := func(hash uint64, j int64, size int) int {
+		return int(int64(hash&0xFFFFFFFF)+j*j) % size
+	}
+	for _, size := range []int{1000, 1024} {
+		// Hashes whose entropy lives entirely in the high 32 bits.
+		full, old := map[int]bool{}, map[int]bool{}
+		for i := range uint64(size) {
+			full[elastichash.QuadraticProbe(i<<32, 0, size)] = true
+			old[masked(i<<32, 0, size)] = true
+		}
+		assert.Len(t, old, 1, "masking collapses every key onto one slot")
+		assert.Greater(t, len(full), size/2, "size %d", size)
+	}
+}
+
+var probeSink int
+
+func BenchmarkQuadraticProbe(b *testing.B) {
+	for i := range b.N {
+		probeSink += elastichash.QuadraticProbe(uint64(i)*0x9E3779B97F4A7C15, int64(i&15), 1009)
+	}
+}
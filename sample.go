@@ -0,0 +1,27 @@
+package elastichash
+
+import "math/rand/v2"
+
+// Sample returns up to k live entries chosen uniformly at random, or every
+// entry when k >= Len. It makes one pass over the levels with reservoir
+// sampling, so only the k-entry result is allocated.
+func (ht *HashTable[K, V]) Sample(k int) []Entry[K, V] {
+	if k <= 0 {
+		return nil
+	}
+	if k >= ht.Len() {
+		return ht.Entries()
+	}
+	sample := make([]Entry[K, V], 0, k)
+	seen := 0
+	ht.Range(func(key K, value V) bool {
+		seen++
+		if len(sample) < k {
+			sample = append(sample, Entry[K, V]{Key: key, Value: value})
+		} else if j := rand.IntN(seen); j < k {
+			sample[j] = Entry[K, V]{Key: key, Value: value}
+		}
+		return true
+	})
+	return sample
+}
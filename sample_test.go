@@ -0,0 +1,52 @@
+package elastichash_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestSample(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](100, 0.1)
+	assert.Empty(t, ht.Sample(5))
+	for i := range 40 {
+		mustInsert(t, ht, i, i*i)
+	}
+
+	assert.Empty(t, ht.Sample(0))
+	assert.ElementsMatch(t, ht.Entries(), ht.Sample(40))
+	assert.ElementsMatch(t, ht.Entries(), ht.Sample(1000))
+
+	sample := ht.Sample(10)
+	require.Len(t, sample, 10)
+	seen := make(map[int]bool)
+	for _, e := range sample {
+		assert.False(t, seen[e.Key], "key %d sampled twice", e.Key)
+		seen[e.Key] = true
+		assert.Equal(t, e.Key*e.Key, e.Value)
+	}
+}
+
+func TestSampleIsUniform(t *testing.T) {
+	const n, k, trials = 20, 5, 20000
+	ht := elastichash.NewHashTable[int, int](50, 0.1)
+	for i := range n {
+		mustInsert(t, ht, i, i)
+	}
+
+	counts := make([]int, n)
+	for range trials {
+		for _, e := range ht.Sample(k) {
+			counts[e.Key]++
+		}
+	}
+	// Each key is expected trials*k/n = 5000 times, with a standard
+	// deviation of about 61; 5% either way is more than six deviations.
+	expected := float64(trials * k / n)
+	for key, count := range counts {
+		assert.InDelta(t, expected, count, expected*0.05, "key %d", key)
+	}
+}
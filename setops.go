@@ -0,0 +1,30 @@
+package elastichash
+
+// Union returns a new table holding every key of a and b, taking b's value
+// for keys in both. The result has a's configuration and is sized to fit.
+func Union[K comparable, V any](a, b *HashTable[K, V]) *HashTable[K, V] {
+	entries := make([]Entry[K, V], 0, a.Len()+b.Len())
+	a.Range(func(key K, value V) bool {
+		if !b.Contains(key) {
+			entries = append(entries, Entry[K, V]{Key: key, Value: value})
+		}
+		return true
+	})
+	b.Range(func(key K, value V) bool {
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+		return true
+	})
+	return a.withEntries(entries)
+}
+
+// Intersection returns a new table holding the keys present in both a and b,
+// with a's values and configuration.
+func Intersection[K comparable, V any](a, b *HashTable[K, V]) *HashTable[K, V] {
+	return a.Filter(func(key K, _ V) bool { return b.Contains(key) })
+}
+
+// Difference returns a new table holding the keys of a that are not in b,
+// with a's values and configuration.
+func Difference[K comparable, V any](a, b *HashTable[K, V]) *HashTable[K, V] {
+	return a.Filter(func(key K, _ V) bool { return !b.Contains(key) })
+}
@@ -0,0 +1,41 @@
+package elastichash_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func table(t *testing.T, keys []int, value int) *elastichash.HashTable[int, int] {
+	t.Helper()
+	ht := elastichash.NewHashTable[int, int](100, 0.1)
+	for _, k := range keys {
+		mustInsert(t, ht, k, value)
+	}
+	return ht
+}
+
+func TestUnion(t *testing.T) {
+	a, b := table(t, []int{1, 2, 3}, 1), table(t, []int{3, 4}, 2)
+	u := elastichash.Union(a, b)
+	assert.Equal(t, map[int]int{1: 1, 2: 1, 3: 2, 4: 2}, u.ToMap(), "b wins on conflict")
+
+	disjoint := elastichash.Union(a, table(t, []int{7, 8}, 3))
+	assert.Equal(t, 5, disjoint.Len())
+	assert.Equal(t, 3, a.Len(), "inputs are unchanged")
+}
+
+func TestIntersection(t *testing.T) {
+	a, b := table(t, []int{1, 2, 3}, 1), table(t, []int{2, 3, 4}, 2)
+	assert.Equal(t, map[int]int{2: 1, 3: 1}, elastichash.Intersection(a, b).ToMap())
+	assert.Zero(t, elastichash.Intersection(a, table(t, []int{7, 8}, 3)).Len())
+}
+
+func TestDifference(t *testing.T) {
+	a, b := table(t, []int{1, 2, 3}, 1), table(t, []int{2, 3, 4}, 2)
+	assert.Equal(t, map[int]int{1: 1}, elastichash.Difference(a, b).ToMap())
+	assert.Equal(t, a.ToMap(), elastichash.Difference(a, table(t, []int{7, 8}, 3)).ToMap())
+	assert.Zero(t, elastichash.Difference(a, a).Len())
+}
@@ -0,0 +1,159 @@
+package elastichash
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math/bits"
+	"sync"
+)
+
+// ShardedHashTable spreads keys over a power-of-two number of independent
+// HashTables, each behind its own lock, so writers to different shards do not
+// contend. A key's shard is chosen by the high bits of its hash.
+type ShardedHashTable[K comparable, V any] struct {
+	shards []shard[K, V]
+	shift  uint
+	// seed, hasher and normalize route keys to shards. They are copied from
+	// the first shard at construction because a shard's own fields are
+	// replaced when it grows, under a lock shardFor does not hold.
+	seed      maphash.Seed
+	hasher    func(K) uint64
+	normalize func(K) K
+}
+
+type shard[K comparable, V any] struct {
+	mu sync.RWMutex
+	ht *HashTable[K, V]
+}
+
+// NewShardedHashTable splits capacity evenly across n shards. It panics if n
+// is not a power of two.
+func NewShardedHashTable[K ValidKey, V any](n, capacity int, delta float64, opts ...Option) *ShardedHashTable[K, V] {
+	if n < 1 || n&(n-1) != 0 {
+		panic(fmt.Sprintf("elastichash: shard count %d is not a power of two", n))
+	}
+	perShard := (capacity + n - 1) / n
+	st := &ShardedHashTable[K, V]{
+		shards: make([]shard[K, V], n),
+		shift:  uint(64 - bits.TrailingZeros(uint(n))),
+	}
+	for i := range st.shards {
+		st.shards[i].ht = NewHashTable[K, V](perShard, delta, opts...)
+	}
+	first := st.shards[0].ht
+	st.seed, st.hasher, st.normalize = first.seed, first.hasher, first.normalize
+	return st
+}
+
+func (st *ShardedHashTable[K, V]) shardFor(key K) *shard[K, V] {
+	if len(st.shards) == 1 {
+		return &st.shards[0]
+	}
+	if st.normalize != nil {
+		key = st.normalize(key)
+	}
+	var h uint64
+	if st.hasher != nil {
+		h = st.hasher(key)
+	} else {
+		h = hashKey(st.seed, key)
+	}
+	return &st.shards[h>>st.shift]
+}
+
+// Insert stores value under key in its shard and reports whether it replaced
+// an existing value.
+func (st *ShardedHashTable[K, V]) Insert(key K, value V) (replaced bool, err error) {
+	s := st.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ht.Insert(key, value)
+}
+
+func (st *ShardedHashTable[K, V]) Update(key K, value V) bool {
+	s := st.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ht.Update(key, value)
+}
+
+// CompareAndSwap is HashTable.CompareAndSwap under the key's shard write
+// lock, so the comparison and the store are atomic with respect to other
+// callers.
+func (st *ShardedHashTable[K, V]) CompareAndSwap(key K, old, new V) bool {
+	s := st.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ht.CompareAndSwap(key, old, new)
+}
+
+// CompareAndDelete is HashTable.CompareAndDelete under the key's shard write
+// lock.
+func (st *ShardedHashTable[K, V]) CompareAndDelete(key K, old V) bool {
+	s := st.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ht.CompareAndDelete(key, old)
+}
+
+func (st *ShardedHashTable[K, V]) Delete(key K) bool {
+	s := st.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ht.Delete(key)
+}
+
+func (st *ShardedHashTable[K, V]) Get(key K) (V, bool) {
+	s := st.shardFor(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ht.Get(key)
+}
+
+func (st *ShardedHashTable[K, V]) Contains(key K) bool {
+	s := st.shardFor(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ht.Contains(key)
+}
+
+// Len sums the live entries of every shard without taking any lock. Shards
+// are read one at a time, so the total is not a snapshot under concurrent
+// writes.
+func (st *ShardedHashTable[K, V]) Len() int {
+	n := 0
+	for i := range st.shards {
+		n += st.shards[i].ht.Len()
+	}
+	return n
+}
+
+// Cap sums the capacity of every shard.
+func (st *ShardedHashTable[K, V]) Cap() int {
+	n := 0
+	for i := range st.shards {
+		s := &st.shards[i]
+		s.mu.RLock()
+		n += s.ht.Cap()
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Range calls f for every live entry, one shard at a time in shard order,
+// holding that shard's read lock; f must not write to the table.
+func (st *ShardedHashTable[K, V]) Range(f func(key K, value V) bool) {
+	for i := range st.shards {
+		s := &st.shards[i]
+		s.mu.RLock()
+		more := true
+		s.ht.Range(func(key K, value V) bool {
+			more = f(key, value)
+			return more
+		})
+		s.mu.RUnlock()
+		if !more {
+			return
+		}
+	}
+}
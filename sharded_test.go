@@ -0,0 +1,184 @@
+package elastichash_test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestShardedHashTable(t *testing.T) {
+	st := elastichash.NewShardedHashTable[int, int](8, 4000, 0.1)
+	assert.GreaterOrEqual(t, st.Cap(), 4000)
+
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 250 {
+				key := w*250 + i
+				_, err := st.Insert(key, key)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 2000, st.Len())
+	for i := range 2000 {
+		v, ok := st.Get(i)
+		require.True(t, ok, "key %d should be retrievable", i)
+		assert.Equal(t, i, v)
+	}
+	assert.True(t, st.Update(5, 50))
+	assert.True(t, st.Delete(6))
+	assert.False(t, st.Contains(6))
+	assert.Equal(t, 1999, st.Len())
+
+	seen := 0
+	st.Range(func(int, int) bool {
+		seen++
+		return true
+	})
+	assert.Equal(t, 1999, seen)
+
+	seen = 0
+	st.Range(func(int, int) bool {
+		seen++
+		return seen < 10
+	})
+	assert.Equal(t, 10, seen)
+}
+
+func TestShardedHashTableRejectsNonPowerOfTwo(t *testing.T) {
+	assert.Panics(t, func() { elastichash.NewShardedHashTable[int, int](3, 100, 0.1) })
+	assert.NotPanics(t, func() { elastichash.NewShardedHashTable[int, int](1, 100, 0.1) })
+}
+
+type concurrentTable interface {
+	Insert(key string, value int) (bool, error)
+	Get(key string) (int, bool)
+}
+
+func benchmarkConcurrent(b *testing.B, table concurrentTable) {
+	keys := make([]string, 4096)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+		if _, err := table.Insert(keys[i], i); err != nil {
+			b.Fatal(err)
+		}
+	}
+	var worker atomic.Int64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(worker.Add(1)) * 7919
+		for pb.Next() {
+			key := keys[i%len(keys)]
+			if i%4 == 0 {
+				table.Insert(key, i)
+			} else {
+				table.Get(key)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkConcurrentHashTable(b *testing.B) {
+	benchmarkConcurrent(b, elastichash.NewConcurrentHashTable[string, int](8192, 0.1))
+}
+
+func BenchmarkShardedHashTable(b *testing.B) {
+	for _, n := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("shards=%d", n), func(b *testing.B) {
+			benchmarkConcurrent(b, elastichash.NewShardedHashTable[string, int](n, 8192, 0.1))
+		})
+	}
+}
+
+func TestShardedInsertReportsReplaced(t *testing.T) {
+	st := elastichash.NewShardedHashTable[string, int](4, 100, 0.1)
+	for i := range 20 {
+		replaced, err := st.Insert(fmt.Sprint(i), i)
+		require.NoError(t, err)
+		assert.False(t, replaced)
+	}
+	for i := range 20 {
+		replaced, err := st.Insert(fmt.Sprint(i), -i)
+		require.NoError(t, err)
+		assert.True(t, replaced)
+	}
+	assert.Equal(t, 20, st.Len())
+}
+
+// TestShardedAutoGrowDuringRouting is meant for -race: routing a key must not
+// read a shard's state while another goroutine grows that shard.
+func TestShardedAutoGrowDuringRouting(t *testing.T) {
+	const workers, perWorker = 4, 500
+	st := elastichash.NewShardedHashTable[int, int](4, 16, 0.1, elastichash.WithAutoGrow())
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWorker {
+				key := w*perWorker + i
+				_, err := st.Insert(key, key)
+				assert.NoError(t, err)
+				st.Contains(key)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, workers*perWorker, st.Len())
+	assert.Greater(t, st.Cap(), 16, "the shards should have grown")
+	for key := range workers * perWorker {
+		v, ok := st.Get(key)
+		require.True(t, ok, "key %d", key)
+		assert.Equal(t, key, v)
+	}
+}
+
+func TestShardedCompareAndSwap(t *testing.T) {
+	const workers, increments = 8, 100
+	st := elastichash.NewShardedHashTable[int, int](4, 100, 0.1)
+	for key := range 4 {
+		_, err := st.Insert(key, 0)
+		require.NoError(t, err)
+	}
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := w % 4
+			for range increments {
+				for {
+					v, _ := st.Get(key)
+					if st.CompareAndSwap(key, v, v+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for key := range 4 {
+		v, _ := st.Get(key)
+		assert.Equal(t, workers/4*increments, v, "no increment to key %d should be lost", key)
+		assert.False(t, st.CompareAndDelete(key, 0))
+		assert.True(t, st.CompareAndDelete(key, v))
+	}
+	assert.False(t, st.CompareAndSwap(0, 0, 1), "a missing key is never inserted")
+	assert.Zero(t, st.Len())
+}
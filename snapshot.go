@@ -0,0 +1,45 @@
+package elastichash
+
+// ReadOnlyTable is a frozen copy of a HashTable taken by Snapshot. It has no
+// mutators, so any number of goroutines may read it without locking while
+// writers carry on with the source table.
+type ReadOnlyTable[K comparable, V any] struct {
+	ht *HashTable[K, V]
+}
+
+// Snapshot copies ht's current contents into a ReadOnlyTable. Later writes to
+// ht do not show up in the snapshot.
+func (ht *HashTable[K, V]) Snapshot() ReadOnlyTable[K, V] {
+	return ReadOnlyTable[K, V]{ht: ht.Clone()}
+}
+
+// Get returns the value stored under key.
+func (rt ReadOnlyTable[K, V]) Get(key K) (V, bool) {
+	return rt.ht.Get(key)
+}
+
+// Contains reports whether key is present.
+func (rt ReadOnlyTable[K, V]) Contains(key K) bool {
+	return rt.ht.Contains(key)
+}
+
+// Len returns the number of entries.
+func (rt ReadOnlyTable[K, V]) Len() int {
+	return rt.ht.Len()
+}
+
+// Range calls f for every entry in unspecified order, stopping early if f
+// returns false.
+func (rt ReadOnlyTable[K, V]) Range(f func(key K, value V) bool) {
+	rt.ht.Range(f)
+}
+
+// Keys returns every key in unspecified order.
+func (rt ReadOnlyTable[K, V]) Keys() []K {
+	return rt.ht.Keys()
+}
+
+// Values returns every value in unspecified order.
+func (rt ReadOnlyTable[K, V]) Values() []V {
+	return rt.ht.Values()
+}
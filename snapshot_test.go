@@ -0,0 +1,69 @@
+package elastichash_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestSnapshot(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.1)
+	for i := range 50 {
+		mustInsert(t, ht, fmt.Sprintf("key%d", i), i)
+	}
+	snap := ht.Snapshot()
+
+	require.True(t, ht.Delete("key0"))
+	require.True(t, ht.Update("key1", -1))
+	mustInsert(t, ht, "new", 100)
+	ht.Reset()
+
+	assert.Equal(t, 50, snap.Len())
+	assert.True(t, snap.Contains("key0"))
+	assert.False(t, snap.Contains("new"))
+	v, ok := snap.Get("key1")
+	require.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.Len(t, snap.Keys(), 50)
+	values := snap.Values()
+	require.Len(t, values, 50)
+	assert.Contains(t, values, 1)
+
+	n := 0
+	snap.Range(func(string, int) bool {
+		n++
+		return n < 10
+	})
+	assert.Equal(t, 10, n)
+}
+
+func TestSnapshotConcurrentReads(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](1000, 0.1)
+	for i := range 500 {
+		mustInsert(t, ht, i, i)
+	}
+	snap := ht.Snapshot()
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 500 {
+				v, ok := snap.Get(i)
+				assert.True(t, ok)
+				assert.Equal(t, i, v)
+			}
+		}()
+	}
+	for i := 500; i < 800; i++ {
+		mustInsert(t, ht, i, i)
+	}
+	wg.Wait()
+	assert.Equal(t, 500, snap.Len())
+}
@@ -0,0 +1,31 @@
+package elastichash
+
+import (
+	"cmp"
+	"slices"
+)
+
+// KeysSorted returns every live key in ascending order, for output that must
+// not depend on the table's layout. Go methods cannot require an ordered key
+// type, so this is a function; KeysSortedFunc covers other key types.
+func KeysSorted[K cmp.Ordered, V any](ht *HashTable[K, V]) []K {
+	keys := ht.Keys()
+	slices.Sort(keys)
+	return keys
+}
+
+// KeysSortedFunc returns every live key ordered by less, which must be a
+// strict weak ordering. Keys that compare equal keep no particular order.
+func (ht *HashTable[K, V]) KeysSortedFunc(less func(a, b K) bool) []K {
+	keys := ht.Keys()
+	slices.SortFunc(keys, func(a, b K) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return 0
+	})
+	return keys
+}
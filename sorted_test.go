@@ -0,0 +1,50 @@
+package elastichash_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestKeysSorted(t *testing.T) {
+	keys := make([]int, 200)
+	for i := range keys {
+		keys[i] = i * 3
+	}
+	shuffled := slices.Clone(keys)
+	rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	ht := elastichash.NewHashTable[int, int](300, 0.1)
+	for _, k := range shuffled {
+		mustInsert(t, ht, k, k)
+	}
+	assert.Equal(t, keys, elastichash.KeysSorted(ht))
+
+	words := elastichash.NewHashTable[string, int](10, 0.1)
+	for _, w := range []string{"pear", "apple", "fig"} {
+		mustInsert(t, words, w, len(w))
+	}
+	assert.Equal(t, []string{"apple", "fig", "pear"}, elastichash.KeysSorted(words))
+	assert.Empty(t, elastichash.KeysSorted(elastichash.NewHashTable[string, int](10, 0.1)))
+}
+
+func TestKeysSortedFunc(t *testing.T) {
+	type point struct{ x, y int }
+	ht := elastichash.NewHashTableWithHasher[point, bool](20, 0.1, func(p point) uint64 {
+		return uint64(p.x)<<32 | uint64(uint32(p.y))
+	})
+	for _, p := range []point{{2, 1}, {1, 5}, {2, 0}, {0, 9}} {
+		mustInsert(t, ht, p, true)
+	}
+	byXThenY := func(a, b point) bool {
+		if a.x != b.x {
+			return a.x < b.x
+		}
+		return a.y < b.y
+	}
+	assert.Equal(t, []point{{0, 9}, {1, 5}, {2, 0}, {2, 1}}, ht.KeysSortedFunc(byXThenY))
+}
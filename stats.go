@@ -0,0 +1,113 @@
+package elastichash
+
+import (
+	"expvar"
+	"reflect"
+	"unsafe"
+)
+
+// Stats is a point-in-time summary of how a table's entries are spread over
+// its levels.
+type Stats struct {
+	Items      int
+	Capacity   int
+	LoadFactor float64
+	Delta      float64
+	C          float64
+	Threshold  float64
+	// Resizes counts the rebuilds the table has gone through, whether from
+	// WithAutoGrow, Resize or Reserve.
+	Resizes int
+	// MaxProbeObserved is the most slots any single Insert has inspected.
+	MaxProbeObserved int
+	// LevelLoads is each level's occupancy over its size, in level order.
+	LevelLoads []float64
+	Levels     []LevelStats
+}
+
+// LevelStats describes a single level.
+type LevelStats struct {
+	Size       int
+	Occupancy  int
+	Tombstones int
+}
+
+// Stats reports per-level sizes and occupancy alongside the table's overall
+// load factor (live entries over capacity) and configuration.
+func (ht *HashTable[K, V]) Stats() Stats {
+	s := Stats{
+		Items:            ht.Len(),
+		Capacity:         ht.capacity,
+		Delta:            ht.delta,
+		C:                ht.c,
+		Threshold:        ht.threshold,
+		Resizes:          ht.resizes,
+		MaxProbeObserved: ht.maxProbes,
+		LevelLoads:       make([]float64, len(ht.levels)),
+		Levels:           make([]LevelStats, len(ht.levels)),
+	}
+	if ht.capacity > 0 {
+		s.LoadFactor = float64(ht.Len()) / float64(ht.capacity)
+	}
+	for i, level := range ht.levels {
+		s.Levels[i] = LevelStats{
+			Size:       len(level),
+			Occupancy:  ht.occupanciesByLevel[i],
+			Tombstones: ht.tombstonesByLevel[i],
+		}
+		if len(level) > 0 {
+			s.LevelLoads[i] = float64(ht.occupanciesByLevel[i]) / float64(len(level))
+		}
+	}
+	return s
+}
+
+// PublishVar registers ht's Stats with expvar under name, so they appear as
+// JSON on /debug/vars. Like expvar.Publish it panics if name is already
+// taken. The stats are read without locking whenever the var is rendered.
+func (ht *HashTable[K, V]) PublishVar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return ht.Stats()
+	}))
+}
+
+// ForEachLevel calls f with the index, size and occupancy of every level in
+// order, for diagnostics that need level geometry without building Stats.
+func (ht *HashTable[K, V]) ForEachLevel(f func(levelIndex, size, occupancy int)) {
+	for i, level := range ht.levels {
+		f(i, len(level), ht.occupanciesByLevel[i])
+	}
+}
+
+// MemoryBytes estimates the heap bytes ht holds: the table header, every
+// level's slots, the per-level counters, the Bloom filter if any, and the
+// bytes of string keys and values, which live outside the slots. Memory that
+// other key and value types point to is not counted.
+func (ht *HashTable[K, V]) MemoryBytes() int {
+	var zero slot[K, V]
+	n := int(unsafe.Sizeof(*ht))
+	n += len(ht.levels) * int(unsafe.Sizeof([]slot[K, V](nil)))
+	n += ht.capacity * int(unsafe.Sizeof(zero))
+	n += len(ht.levels) * int(unsafe.Sizeof(int(0))*2+unsafe.Sizeof(int64(0))*2)
+	if ht.bloom != nil {
+		n += len(ht.bloom.bits) * int(unsafe.Sizeof(uint64(0)))
+	}
+	keyIsString := reflect.TypeFor[K]().Kind() == reflect.String
+	valueIsString := reflect.TypeFor[V]().Kind() == reflect.String
+	if keyIsString || valueIsString {
+		for _, level := range ht.levels {
+			for _, e := range level {
+				if e.state != occupied {
+					continue
+				}
+				if keyIsString {
+					n += reflect.ValueOf(e.key).Len()
+				}
+				if valueIsString {
+					n += reflect.ValueOf(e.value).Len()
+				}
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,177 @@
+package elastichash_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestStats(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](1000, 0.1)
+	for i := range 600 {
+		mustInsert(t, ht, i, i)
+	}
+	for i := range 50 {
+		require.True(t, ht.Delete(i))
+	}
+
+	s := ht.Stats()
+	assert.Equal(t, 550, s.Items)
+	assert.Equal(t, 1000, s.Capacity)
+	assert.InDelta(t, 0.55, s.LoadFactor, 1e-9)
+	assert.Equal(t, 0.1, s.Delta)
+	assert.Equal(t, 4.0, s.C)
+	require.NotEmpty(t, s.Levels)
+
+	size, occupancy, tombstones := 0, 0, 0
+	for _, l := range s.Levels {
+		size += l.Size
+		occupancy += l.Occupancy
+		tombstones += l.Tombstones
+	}
+	assert.Equal(t, ht.Cap(), size)
+	assert.Equal(t, ht.Len(), occupancy)
+	assert.Equal(t, 50, tombstones)
+}
+
+func TestProbeCounts(t *testing.T) {
+	ht := elastichash.NewHashTable[string, int](100, 0.1)
+	replaced, probes, err := ht.InsertWithProbes("key1", 1)
+	require.NoError(t, err)
+	assert.False(t, replaced)
+	assert.Positive(t, probes)
+
+	// The only key in an empty table sits at the first slot of its sequence.
+	v, ok, probes := ht.GetWithProbes("key1")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 1, probes)
+
+	replaced, probes, err = ht.InsertWithProbes("key1", 2)
+	require.NoError(t, err)
+	assert.True(t, replaced)
+	assert.Equal(t, 1, probes, "replacing should only cost the search")
+
+	_, ok, probes = ht.GetWithProbes("missing")
+	assert.False(t, ok)
+	assert.GreaterOrEqual(t, probes, len(ht.Stats().Levels), "a miss checks at least one slot per level")
+}
+
+func TestCachedProbeLimits(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](1000, 0.1, elastichash.WithC(2))
+	check := func() {
+		t.Helper()
+		cached, fresh := ht.ProbeLimits()
+		require.Equal(t, fresh, cached)
+	}
+	check()
+	for i := range 800 {
+		mustInsert(t, ht, i, i)
+		if i%50 == 0 {
+			check()
+		}
+	}
+	for i := range 400 {
+		require.True(t, ht.Delete(i))
+	}
+	check()
+	for i := range 300 {
+		mustInsert(t, ht, -i-1, i)
+	}
+	check()
+	require.NoError(t, ht.Resize(2000))
+	check()
+	ht.Reset()
+	check()
+}
+
+func TestPublishVar(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](100, 0.1)
+	ht.PublishVar("elastichash_test_table")
+	for i := range 7 {
+		mustInsert(t, ht, i, i)
+	}
+
+	v := expvar.Get("elastichash_test_table")
+	require.NotNil(t, v)
+	var stats elastichash.Stats
+	require.NoError(t, json.Unmarshal([]byte(v.String()), &stats))
+	assert.Equal(t, 7, stats.Items)
+	assert.Equal(t, ht.Stats(), stats)
+
+	assert.Panics(t, func() { ht.PublishVar("elastichash_test_table") })
+}
+
+func TestLevelLoads(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](1000, 0.1)
+	maxProbes := 0
+	for i := range 300 {
+		_, probes, err := ht.InsertWithProbes(i, i)
+		require.NoError(t, err)
+		maxProbes = max(maxProbes, probes)
+	}
+	s := ht.Stats()
+	require.Len(t, s.LevelLoads, len(s.Levels))
+	for i, l := range s.Levels {
+		assert.InDelta(t, float64(l.Occupancy)/float64(l.Size), s.LevelLoads[i], 1e-9)
+	}
+	assert.Greater(t, s.LevelLoads[0], s.LevelLoads[len(s.LevelLoads)-1], "early levels fill first")
+	assert.Equal(t, maxProbes, s.MaxProbeObserved)
+}
+
+func TestLevelSizesSumToCapacity(t *testing.T) {
+	for capacity := 1; capacity <= 1000; capacity++ {
+		sum := 0
+		for _, l := range elastichash.NewHashTable[int, int](capacity, 0.1).Stats().Levels {
+			require.Positive(t, l.Size, "capacity %d", capacity)
+			sum += l.Size
+		}
+		require.Equal(t, capacity, sum, "capacity %d", capacity)
+	}
+}
+
+func TestForEachLevel(t *testing.T) {
+	ht := elastichash.NewHashTable[int, int](1000, 0.1)
+	for i := range 600 {
+		mustInsert(t, ht, i, i)
+	}
+	levels := ht.Stats().Levels
+	calls, occupied := 0, 0
+	ht.ForEachLevel(func(i, size, occupancy int) {
+		assert.Equal(t, calls, i)
+		assert.Equal(t, levels[i].Size, size)
+		assert.Equal(t, levels[i].Occupancy, occupancy)
+		calls++
+		occupied += occupancy
+	})
+	assert.Equal(t, len(levels), calls)
+	assert.Equal(t, ht.Len(), occupied)
+}
+
+func TestMemoryBytes(t *testing.T) {
+	// An int slot is its key, value and cached hash plus a state byte,
+	// padded to 32 bytes.
+	const slotBytes = 32
+	ints := elastichash.NewHashTable[int, int](1000, 0.1)
+	estimate := ints.MemoryBytes()
+	assert.GreaterOrEqual(t, estimate, 1000*slotBytes)
+	assert.Less(t, estimate, 1000*slotBytes+2000, "overhead beyond the slots should be small")
+
+	doubled := elastichash.NewHashTable[int, int](2000, 0.1)
+	assert.InDelta(t, 1000*slotBytes, doubled.MemoryBytes()-estimate, 200, "the estimate should scale with capacity")
+
+	strs := elastichash.NewHashTable[string, string](100, 0.1)
+	empty := strs.MemoryBytes()
+	mustInsert(t, strs, "0123456789", "abcdefghijklmnopqrst")
+	assert.Equal(t, empty+30, strs.MemoryBytes(), "string bytes should be counted per entry")
+	require.True(t, strs.Delete("0123456789"))
+	assert.Equal(t, empty, strs.MemoryBytes())
+
+	bloom := elastichash.NewHashTable[int, int](1000, 0.1, elastichash.WithBloomFilter())
+	assert.Greater(t, bloom.MemoryBytes(), estimate)
+}
@@ -0,0 +1,95 @@
+package elastichash
+
+// SyncMapAdapter wraps a ConcurrentHashTable in the method set of sync.Map,
+// with typed keys and values, so code written against sync.Map can switch by
+// changing its declarations. The table grows automatically because Store has
+// no way to report a full table.
+type SyncMapAdapter[K comparable, V any] struct {
+	c *ConcurrentHashTable[K, V]
+}
+
+func NewSyncMapAdapter[K ValidKey, V any](capacity int, delta float64, opts ...Option) *SyncMapAdapter[K, V] {
+	opts = append(opts[:len(opts):len(opts)], WithAutoGrow())
+	return &SyncMapAdapter[K, V]{c: NewConcurrentHashTable[K, V](capacity, delta, opts...)}
+}
+
+// Load returns the value stored under key, if any.
+func (m *SyncMapAdapter[K, V]) Load(key K) (value V, ok bool) {
+	return m.c.Get(key)
+}
+
+// Store sets the value for key.
+func (m *SyncMapAdapter[K, V]) Store(key K, value V) {
+	m.c.mu.Lock()
+	defer m.c.mu.Unlock()
+	m.store(key, value)
+}
+
+// LoadOrStore returns the existing value for key with loaded true, or stores
+// value and returns it with loaded false.
+func (m *SyncMapAdapter[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	m.c.mu.Lock()
+	defer m.c.mu.Unlock()
+	if v, ok := m.c.ht.Get(key); ok {
+		return v, true
+	}
+	m.store(key, value)
+	return value, false
+}
+
+// Swap stores value under key and returns the previous value, if any.
+func (m *SyncMapAdapter[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	m.c.mu.Lock()
+	defer m.c.mu.Unlock()
+	previous, loaded, err := m.c.ht.Swap(key, value)
+	if err != nil {
+		panic(err)
+	}
+	return previous, loaded
+}
+
+// LoadAndDelete removes key, returning its previous value if it was present.
+func (m *SyncMapAdapter[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	m.c.mu.Lock()
+	defer m.c.mu.Unlock()
+	return m.c.ht.Pop(key)
+}
+
+// CompareAndSwap stores new under key if its value is == old. It panics if V
+// is not comparable.
+func (m *SyncMapAdapter[K, V]) CompareAndSwap(key K, old, new V) (swapped bool) {
+	return m.c.CompareAndSwap(key, old, new)
+}
+
+// CompareAndDelete removes key if its value is == old. It panics if V is not
+// comparable.
+func (m *SyncMapAdapter[K, V]) CompareAndDelete(key K, old V) (deleted bool) {
+	return m.c.CompareAndDelete(key, old)
+}
+
+// Delete removes key.
+func (m *SyncMapAdapter[K, V]) Delete(key K) {
+	m.c.Delete(key)
+}
+
+// Range calls f for every entry, stopping early if f returns false. As with
+// sync.Map, the entries are not a consistent snapshot and f may call any
+// other method, including Store and Delete.
+func (m *SyncMapAdapter[K, V]) Range(f func(key K, value V) bool) {
+	m.c.mu.RLock()
+	entries := m.c.ht.Entries()
+	m.c.mu.RUnlock()
+	for _, e := range entries {
+		if !f(e.Key, e.Value) {
+			return
+		}
+	}
+}
+
+// store inserts under the held write lock. The table grows on demand, so an
+// error here is a bug rather than a full table.
+func (m *SyncMapAdapter[K, V]) store(key K, value V) {
+	if _, err := m.c.ht.Insert(key, value); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,115 @@
+package elastichash_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+func TestSyncMapAdapter(t *testing.T) {
+	m := elastichash.NewSyncMapAdapter[string, int](2, 0.1)
+
+	_, ok := m.Load("a")
+	assert.False(t, ok)
+
+	m.Store("a", 1)
+	v, ok := m.Load("a")
+	require.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	m.Store("a", 2)
+	v, _ = m.Load("a")
+	assert.Equal(t, 2, v)
+
+	actual, loaded := m.LoadOrStore("a", 3)
+	assert.True(t, loaded)
+	assert.Equal(t, 2, actual)
+	actual, loaded = m.LoadOrStore("b", 4)
+	assert.False(t, loaded)
+	assert.Equal(t, 4, actual)
+
+	previous, loaded := m.Swap("b", 5)
+	assert.True(t, loaded)
+	assert.Equal(t, 4, previous)
+	_, loaded = m.Swap("c", 6)
+	assert.False(t, loaded)
+	m.Delete("c")
+
+	v, loaded = m.LoadAndDelete("b")
+	assert.True(t, loaded)
+	assert.Equal(t, 5, v)
+	_, loaded = m.LoadAndDelete("b")
+	assert.False(t, loaded)
+
+	assert.False(t, m.CompareAndSwap("a", 1, 10))
+	assert.True(t, m.CompareAndSwap("a", 2, 10))
+	assert.False(t, m.CompareAndDelete("a", 2))
+	assert.True(t, m.CompareAndDelete("a", 10))
+	m.Store("a", 1)
+
+	m.Delete("a")
+	m.Delete("never stored")
+	_, ok = m.Load("a")
+	assert.False(t, ok)
+}
+
+func TestSyncMapAdapterRange(t *testing.T) {
+	m := elastichash.NewSyncMapAdapter[int, int](4, 0.1)
+	for i := range 100 {
+		m.Store(i, i*i)
+	}
+
+	seen := make(map[int]int)
+	m.Range(func(key, value int) bool {
+		seen[key] = value
+		// Writing from inside Range must not deadlock.
+		m.Delete(key)
+		return true
+	})
+	assert.Len(t, seen, 100)
+	assert.Equal(t, 81, seen[9])
+	_, ok := m.Load(9)
+	assert.False(t, ok)
+
+	m.Store(1, 1)
+	m.Store(2, 2)
+	calls := 0
+	m.Range(func(int, int) bool {
+		calls++
+		return false
+	})
+	assert.Equal(t, 1, calls)
+}
+
+func TestSyncMapAdapterConcurrent(t *testing.T) {
+	const workers, perWorker = 8, 200
+	m := elastichash.NewSyncMapAdapter[int, int](16, 0.1)
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWorker {
+				key := w*perWorker + i
+				m.Store(key, key)
+				actual, loaded := m.LoadOrStore(key, -1)
+				assert.True(t, loaded)
+				assert.Equal(t, key, actual)
+			}
+		}()
+	}
+	wg.Wait()
+
+	count := 0
+	m.Range(func(key, value int) bool {
+		assert.Equal(t, key, value)
+		count++
+		return true
+	})
+	assert.Equal(t, workers*perWorker, count)
+}
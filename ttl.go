@@ -0,0 +1,106 @@
+package elastichash
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TTLHashTable is a HashTable whose entries expire a fixed duration after
+// they were last inserted. Expired entries read as absent; Get reclaims the
+// slot of an expired key it finds, Insert sweeps expired entries before
+// giving up on a full table, and Sweep reclaims them all on demand. Like
+// HashTable it is not safe for concurrent use, so there is no background
+// sweeper; call Sweep from the goroutine that owns the table.
+type TTLHashTable[K comparable, V any] struct {
+	ht  *HashTable[K, ttlValue[V]]
+	ttl time.Duration
+	now func() time.Time
+}
+
+type ttlValue[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// NewTTLHashTable returns an empty table whose entries expire ttl after
+// insertion. It panics on invalid arguments, including a ttl that is not
+// positive.
+func NewTTLHashTable[K ValidKey, V any](capacity int, delta float64, ttl time.Duration, opts ...Option) *TTLHashTable[K, V] {
+	if ttl <= 0 {
+		panic(fmt.Errorf("elastichash: ttl must be positive, got %v", ttl))
+	}
+	return &TTLHashTable[K, V]{
+		ht:  NewHashTable[K, ttlValue[V]](capacity, delta, opts...),
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// Insert stores value under key with a fresh expiry and reports whether it
+// replaced an unexpired value.
+func (tt *TTLHashTable[K, V]) Insert(key K, value V) (replaced bool, err error) {
+	now := tt.now()
+	old, ok := tt.ht.Get(key)
+	replaced = ok && tt.live(old, now)
+	v := ttlValue[V]{value: value, expires: now.Add(tt.ttl)}
+	_, err = tt.ht.Insert(key, v)
+	if (errors.Is(err, OutOfSpaceErr) || errors.Is(err, FailedToInsertErr)) && tt.sweep(now) > 0 {
+		_, err = tt.ht.Insert(key, v)
+	}
+	return replaced, err
+}
+
+// Get returns the value stored under key if it has not expired. An expired
+// entry is deleted on the way out.
+func (tt *TTLHashTable[K, V]) Get(key K) (V, bool) {
+	v, ok := tt.ht.Get(key)
+	if !ok {
+		return *new(V), false
+	}
+	if !tt.live(v, tt.now()) {
+		tt.ht.Delete(key)
+		return *new(V), false
+	}
+	return v.value, true
+}
+
+// Delete removes key and reports whether it held an unexpired value.
+func (tt *TTLHashTable[K, V]) Delete(key K) bool {
+	v, ok := tt.ht.Pop(key)
+	return ok && tt.live(v, tt.now())
+}
+
+// Sweep deletes every expired entry and returns how many it removed.
+func (tt *TTLHashTable[K, V]) Sweep() int {
+	return tt.sweep(tt.now())
+}
+
+func (tt *TTLHashTable[K, V]) sweep(now time.Time) int {
+	var expired []K
+	tt.ht.Range(func(key K, v ttlValue[V]) bool {
+		if !tt.live(v, now) {
+			expired = append(expired, key)
+		}
+		return true
+	})
+	for _, key := range expired {
+		tt.ht.Delete(key)
+	}
+	return len(expired)
+}
+
+func (tt *TTLHashTable[K, V]) live(v ttlValue[V], now time.Time) bool {
+	return now.Before(v.expires)
+}
+
+// Len returns the number of stored entries, including expired ones that have
+// not been reclaimed yet; call Sweep first for an exact count.
+func (tt *TTLHashTable[K, V]) Len() int {
+	return tt.ht.Len()
+}
+
+// Cap returns the configured capacity.
+func (tt *TTLHashTable[K, V]) Cap() int {
+	return tt.ht.Cap()
+}
@@ -0,0 +1,105 @@
+package elastichash_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	elastichash "github.com/jaronoff97/elastic-hash"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func newTTLTable(capacity int, ttl time.Duration) (*elastichash.TTLHashTable[string, int], *fakeClock) {
+	tt := elastichash.NewTTLHashTable[string, int](capacity, 0.1, ttl)
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	tt.SetClock(clock.now)
+	return tt, clock
+}
+
+func TestTTLHashTableExpiry(t *testing.T) {
+	tt, clock := newTTLTable(20, time.Second)
+
+	replaced, err := tt.Insert("a", 1)
+	require.NoError(t, err)
+	assert.False(t, replaced)
+
+	clock.t = clock.t.Add(500 * time.Millisecond)
+	v, ok := tt.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	replaced, err = tt.Insert("a", 2)
+	require.NoError(t, err)
+	assert.True(t, replaced, "a has not expired yet")
+
+	clock.t = clock.t.Add(999 * time.Millisecond)
+	_, ok = tt.Get("a")
+	assert.True(t, ok, "re-inserting should refresh the expiry")
+
+	clock.t = clock.t.Add(time.Millisecond)
+	_, ok = tt.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, tt.Len(), "Get should reclaim the expired slot")
+
+	_, err = tt.Insert("b", 3)
+	require.NoError(t, err)
+	clock.t = clock.t.Add(time.Hour)
+	assert.False(t, tt.Delete("b"), "an expired entry is not reported as deleted")
+	replaced, err = tt.Insert("b", 4)
+	require.NoError(t, err)
+	assert.False(t, replaced)
+}
+
+func TestTTLHashTableSlotsAreReusable(t *testing.T) {
+	tt, clock := newTTLTable(10, time.Minute)
+	n := 0
+	for ; ; n++ {
+		if _, err := tt.Insert(string(rune('a'+n)), n); err != nil {
+			assert.ErrorIs(t, err, elastichash.OutOfSpaceErr)
+			break
+		}
+	}
+	require.Positive(t, n)
+
+	clock.t = clock.t.Add(time.Minute)
+	for i := range n {
+		_, err := tt.Insert(string(rune('A'+i)), i)
+		require.NoError(t, err, "expired entries should make room")
+	}
+	assert.Equal(t, n, tt.Len())
+	_, ok := tt.Get("a")
+	assert.False(t, ok)
+	v, ok := tt.Get("B")
+	require.True(t, ok)
+	assert.Equal(t, 1, v)
+}
+
+func TestTTLHashTableSweep(t *testing.T) {
+	tt, clock := newTTLTable(50, time.Second)
+	for i := range 10 {
+		_, err := tt.Insert(string(rune('a'+i)), i)
+		require.NoError(t, err)
+	}
+	clock.t = clock.t.Add(2 * time.Second)
+	for i := range 5 {
+		_, err := tt.Insert(string(rune('A'+i)), i)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 15, tt.Len())
+	assert.Equal(t, 10, tt.Sweep())
+	assert.Equal(t, 5, tt.Len())
+	assert.Equal(t, 0, tt.Sweep())
+}
+
+func TestNewTTLHashTableRejectsNonPositiveTTL(t *testing.T) {
+	assert.Panics(t, func() { elastichash.NewTTLHashTable[string, int](10, 0.1, 0) })
+}